@@ -1,5 +1,5 @@
 // Handles audio playback
 package discordgowrap
 
-func PlayAudioFile(v voiceConnection, filename string) {
+func PlayAudioFile(v *voiceConnection, filename string) {
 }
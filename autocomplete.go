@@ -0,0 +1,140 @@
+// Handles autocomplete interactions
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Limits of autocomplete results
+const (
+	maxAutocompleteChoices = 25
+	maxChoiceLength        = 100
+)
+
+// Returns the choices for the partial value the user typed so far
+type AutocompleteProvider func(c *AutocompleteContext) ([]*CommandChoice, error)
+
+// Passed to autocomplete providers, Route is the command the option belongs to
+type AutocompleteContext struct {
+	InteractionContext
+	Data    *ApplicationCommandData
+	Options []*InteractionOption // the options of the command filled in so far
+	Focused *InteractionOption   // the option being autocompleted
+	Partial string               // what the user typed so far in the focused option
+}
+
+type autocompleteKey struct {
+	path   string
+	option string
+}
+
+// Registers a provider for an option of a command. The option is marked as
+// autocompleted in the definitions from Commands, and can't have choices.
+//
+//	r.Autocomplete("play", "song", func(c *discordgowrap.AutocompleteContext) ([]*discordgowrap.CommandChoice, error) {
+//		var choices []*discordgowrap.CommandChoice
+//		for _, song := range songs {
+//			if strings.Contains(strings.ToLower(song), strings.ToLower(c.Partial)) {
+//				choices = append(choices, &discordgowrap.CommandChoice{Name: song, Value: song})
+//			}
+//		}
+//		return choices, nil
+//	})
+//
+// Only the first 25 choices are sent. Names are cut off at 100 characters, and
+// choices with string values longer than 100 characters are left out.
+func (r *Router) Autocomplete(path string, option string, provider AutocompleteProvider) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.autocomplete[autocompleteKey{joinPath(path), option}] = provider
+}
+
+// Returns options with those that have a provider marked as autocompleted
+func (r *Router) autocompleteOptions(path string, options []*CommandOption) []*CommandOption {
+	marked := make([]*CommandOption, 0, len(options))
+	for _, o := range options {
+		if _, exists := r.autocomplete[autocompleteKey{path, o.Name}]; exists {
+			copied := *o
+			copied.Autocomplete = true
+			copied.Choices = nil
+			o = &copied
+		}
+		marked = append(marked, o)
+	}
+	return marked
+}
+
+// Sends the choices as the response to an autocomplete interaction
+func (s *Session) RespondAutocomplete(ctx context.Context, i *Interaction, choices []*CommandChoice, opts ...RequestOption) error {
+	return s.Respond(ctx, i, InteractionResponse{
+		Type: InteractionResponseAutocompleteResult,
+		Data: &InteractionResponseData{Choices: limitChoices(choices)},
+	}, opts...)
+}
+
+// Applies Discord's limits to autocomplete choices
+func limitChoices(choices []*CommandChoice) []*CommandChoice {
+	limited := make([]*CommandChoice, 0, min(len(choices), maxAutocompleteChoices))
+	for _, choice := range choices {
+		if len(limited) == maxAutocompleteChoices {
+			break
+		}
+		if value, ok := choice.Value.(string); ok && len([]rune(value)) > maxChoiceLength {
+			log.Printf("[Router] Leaving out autocomplete choice %q, its value is longer than %d characters\n", choice.Name, maxChoiceLength)
+			continue
+		}
+		if name := []rune(choice.Name); len(name) > maxChoiceLength {
+			copied := *choice
+			copied.Name = string(name[:maxChoiceLength-1]) + "…"
+			choice = &copied
+		}
+		limited = append(limited, choice)
+	}
+	return limited
+}
+
+func (r *Router) handleAutocomplete(ctx context.Context, i *Interaction) error {
+	data, err := i.CommandData()
+	if err != nil {
+		return err
+	}
+	path, options := invokedCommand(data)
+	var focused *InteractionOption
+	for _, o := range options {
+		if o.Focused {
+			focused = o
+		}
+	}
+	if focused == nil {
+		return fmt.Errorf("no focused option in autocomplete for /%s", path)
+	}
+
+	r.mutex.RLock()
+	provider, exists := r.autocomplete[autocompleteKey{path, focused.Name}]
+	r.mutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("no autocomplete provider for option %s of /%s", focused.Name, path)
+	}
+
+	c := &AutocompleteContext{
+		InteractionContext: InteractionContext{
+			Context:     ctx,
+			Session:     r.session,
+			Interaction: i,
+			Route:       "/" + path,
+		},
+		Data:    data,
+		Options: options,
+		Focused: focused,
+		Partial: focused.StringValue(),
+	}
+	choices, err := provider(c)
+	if err != nil {
+		r.handleError(&c.InteractionContext, err)
+		return nil
+	}
+	r.handleError(&c.InteractionContext, r.session.RespondAutocomplete(c, i, choices))
+	return nil
+}
@@ -0,0 +1,264 @@
+// Handles channels
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// https://discord.com/developers/docs/resources/channel#channel-object-channel-types
+const (
+	ChannelTypeGuildText          = 0
+	ChannelTypeDM                 = 1
+	ChannelTypeGuildVoice         = 2
+	ChannelTypeGroupDM            = 3
+	ChannelTypeGuildCategory      = 4
+	ChannelTypeGuildAnnouncement  = 5
+	ChannelTypeAnnouncementThread = 10
+	ChannelTypePublicThread       = 11
+	ChannelTypePrivateThread      = 12
+	ChannelTypeGuildStageVoice    = 13
+	ChannelTypeGuildDirectory     = 14
+	ChannelTypeGuildForum         = 15
+	ChannelTypeGuildMedia         = 16
+)
+
+// https://discord.com/developers/docs/resources/channel#overwrite-object
+const (
+	OverwriteTypeRole   = 0
+	OverwriteTypeMember = 1
+)
+
+// https://discord.com/developers/docs/resources/channel#channel-object
+type Channel struct {
+	ID                         string                `json:"id"`
+	Type                       int                   `json:"type"`
+	GuildID                    string                `json:"guild_id,omitempty"`
+	Position                   int                   `json:"position,omitempty"`
+	PermissionOverwrites       []PermissionOverwrite `json:"permission_overwrites,omitempty"`
+	Name                       string                `json:"name,omitempty"`
+	Topic                      string                `json:"topic,omitempty"`
+	NSFW                       bool                  `json:"nsfw,omitempty"`
+	LastMessageID              string                `json:"last_message_id,omitempty"`
+	Bitrate                    int                   `json:"bitrate,omitempty"`
+	UserLimit                  int                   `json:"user_limit,omitempty"`
+	RateLimitPerUser           int                   `json:"rate_limit_per_user,omitempty"`
+	Recipients                 []User                `json:"recipients,omitempty"`
+	Icon                       string                `json:"icon,omitempty"`
+	OwnerID                    string                `json:"owner_id,omitempty"`
+	ApplicationID              string                `json:"application_id,omitempty"`
+	ParentID                   string                `json:"parent_id,omitempty"`
+	LastPinTimestamp           *time.Time            `json:"last_pin_timestamp,omitempty"`
+	RTCRegion                  string                `json:"rtc_region,omitempty"`
+	VideoQualityMode           int                   `json:"video_quality_mode,omitempty"`
+	DefaultAutoArchiveDuration int                   `json:"default_auto_archive_duration,omitempty"`
+	Flags                      int                   `json:"flags,omitempty"`
+
+	// Threads only
+	ThreadMetadata   *ThreadMetadata `json:"thread_metadata,omitempty"`
+	Member           *ThreadMember   `json:"member,omitempty"` // set if the bot has joined the thread
+	MessageCount     int             `json:"message_count,omitempty"`
+	MemberCount      int             `json:"member_count,omitempty"`
+	TotalMessageSent int             `json:"total_message_sent,omitempty"`
+	AppliedTags      []string        `json:"applied_tags,omitempty"`
+
+	// Forum and media channels only
+	AvailableTags        []ForumTag    `json:"available_tags,omitempty"`
+	DefaultReactionEmoji *DefaultEmoji `json:"default_reaction_emoji,omitempty"`
+	DefaultSortOrder     *int          `json:"default_sort_order,omitempty"`
+	DefaultForumLayout   int           `json:"default_forum_layout,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#default-reaction-object
+type DefaultEmoji struct {
+	EmojiID   string `json:"emoji_id,omitempty"`
+	EmojiName string `json:"emoji_name,omitempty"`
+}
+
+// Returns whether the channel is a thread
+func (c *Channel) IsThread() bool {
+	switch c.Type {
+	case ChannelTypeAnnouncementThread, ChannelTypePublicThread, ChannelTypePrivateThread:
+		return true
+	}
+	return false
+}
+
+// Returns the string that links the channel in a message
+func (c *Channel) Mention() string {
+	return "<#" + c.ID + ">"
+}
+
+// https://discord.com/developers/docs/resources/channel#overwrite-object
+// ID is a role or user ID depending on Type.
+type PermissionOverwrite struct {
+	ID    string      `json:"id"`
+	Type  int         `json:"type"`
+	Allow Permissions `json:"allow"`
+	Deny  Permissions `json:"deny"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#channel-create
+type ChannelCreate struct {
+	Channel
+}
+
+func (*ChannelCreate) EventType() string { return TypeChannelCreate }
+
+// https://discord.com/developers/docs/events/gateway-events#channel-update
+type ChannelUpdate struct {
+	Channel
+}
+
+func (*ChannelUpdate) EventType() string { return TypeChannelUpdate }
+
+// https://discord.com/developers/docs/events/gateway-events#channel-delete
+type ChannelDelete struct {
+	Channel
+}
+
+func (*ChannelDelete) EventType() string { return TypeChannelDelete }
+
+// https://discord.com/developers/docs/resources/guild#create-guild-channel-json-params
+type ChannelCreateParams struct {
+	Name                       string                `json:"name"`
+	Type                       int                   `json:"type"`
+	Topic                      string                `json:"topic,omitempty"`
+	Bitrate                    int                   `json:"bitrate,omitempty"`
+	UserLimit                  int                   `json:"user_limit,omitempty"`
+	RateLimitPerUser           int                   `json:"rate_limit_per_user,omitempty"`
+	Position                   int                   `json:"position,omitempty"`
+	PermissionOverwrites       []PermissionOverwrite `json:"permission_overwrites,omitempty"`
+	ParentID                   string                `json:"parent_id,omitempty"`
+	NSFW                       bool                  `json:"nsfw,omitempty"`
+	RTCRegion                  string                `json:"rtc_region,omitempty"`
+	DefaultAutoArchiveDuration int                   `json:"default_auto_archive_duration,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#modify-channel-json-params-guild-channel
+// Nil fields are left unchanged.
+type ChannelEdit struct {
+	Name                       string                `json:"name,omitempty"`
+	Type                       *int                  `json:"type,omitempty"`
+	Position                   *int                  `json:"position,omitempty"`
+	Topic                      *string               `json:"topic,omitempty"`
+	NSFW                       *bool                 `json:"nsfw,omitempty"`
+	RateLimitPerUser           *int                  `json:"rate_limit_per_user,omitempty"`
+	Bitrate                    *int                  `json:"bitrate,omitempty"`
+	UserLimit                  *int                  `json:"user_limit,omitempty"`
+	PermissionOverwrites       []PermissionOverwrite `json:"permission_overwrites,omitempty"`
+	ParentID                   *string               `json:"parent_id,omitempty"`
+	RTCRegion                  *string               `json:"rtc_region,omitempty"`
+	DefaultAutoArchiveDuration *int                  `json:"default_auto_archive_duration,omitempty"`
+	Flags                      *int                  `json:"flags,omitempty"`
+
+	// Threads only
+	Archived            *bool    `json:"archived,omitempty"`
+	AutoArchiveDuration *int     `json:"auto_archive_duration,omitempty"`
+	Locked              *bool    `json:"locked,omitempty"`
+	Invitable           *bool    `json:"invitable,omitempty"`
+	AppliedTags         []string `json:"applied_tags,omitempty"`
+
+	// Forum and media channels only
+	AvailableTags []ForumTag `json:"available_tags,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#followed-channel-object
+type FollowedChannel struct {
+	ChannelID string `json:"channel_id"`
+	WebhookID string `json:"webhook_id"`
+}
+
+func (s *Session) GetChannel(ctx context.Context, channelID string, opts ...RequestOption) (*Channel, error) {
+	url := fmt.Sprintf("%s/channels/%s", apiBase, channelID)
+	var channel Channel
+	if err := s.requestJSON(ctx, "GET", url, nil, &channel, opts...); err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+func (s *Session) CreateChannel(ctx context.Context, guildID string, data ChannelCreateParams, opts ...RequestOption) (*Channel, error) {
+	url := fmt.Sprintf("%s/guilds/%s/channels", apiBase, guildID)
+	var channel Channel
+	if err := s.requestJSON(ctx, "POST", url, data, &channel, opts...); err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+func (s *Session) ModifyChannel(ctx context.Context, channelID string, data ChannelEdit, opts ...RequestOption) (*Channel, error) {
+	url := fmt.Sprintf("%s/channels/%s", apiBase, channelID)
+	var channel Channel
+	if err := s.requestJSON(ctx, "PATCH", url, data, &channel, opts...); err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// Deletes a guild channel or closes a DM, returns the deleted channel
+func (s *Session) DeleteChannel(ctx context.Context, channelID string, opts ...RequestOption) (*Channel, error) {
+	url := fmt.Sprintf("%s/channels/%s", apiBase, channelID)
+	var channel Channel
+	if err := s.requestJSON(ctx, "DELETE", url, nil, &channel, opts...); err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// Creates or replaces the permission overwrite for a role or member.
+// The ID of the overwrite is taken from overwrite.ID.
+func (s *Session) EditChannelPermissions(ctx context.Context, channelID string, overwrite PermissionOverwrite, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/permissions/%s", apiBase, channelID, overwrite.ID)
+	data := struct {
+		Allow Permissions `json:"allow"`
+		Deny  Permissions `json:"deny"`
+		Type  int         `json:"type"`
+	}{overwrite.Allow, overwrite.Deny, overwrite.Type}
+	return s.requestJSON(ctx, "PUT", url, data, nil, opts...)
+}
+
+func (s *Session) DeleteChannelPermission(ctx context.Context, channelID string, overwriteID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/permissions/%s", apiBase, channelID, overwriteID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+// Shows the typing indicator in a channel for 10 seconds or until a message is sent
+func (s *Session) TriggerTyping(ctx context.Context, channelID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/typing", apiBase, channelID)
+	return s.requestJSON(ctx, "POST", url, nil, nil, opts...)
+}
+
+// Follows an announcement channel, crossposting its messages to targetChannelID
+func (s *Session) FollowAnnouncementChannel(ctx context.Context, channelID string, targetChannelID string, opts ...RequestOption) (*FollowedChannel, error) {
+	url := fmt.Sprintf("%s/channels/%s/followers", apiBase, channelID)
+	data := struct {
+		WebhookChannelID string `json:"webhook_channel_id"`
+	}{targetChannelID}
+	var followed FollowedChannel
+	if err := s.requestJSON(ctx, "POST", url, data, &followed, opts...); err != nil {
+		return nil, err
+	}
+	return &followed, nil
+}
+
+// Returns the pinned messages in a channel
+func (s *Session) ChannelPins(ctx context.Context, channelID string, opts ...RequestOption) ([]Message, error) {
+	url := fmt.Sprintf("%s/channels/%s/pins", apiBase, channelID)
+	var messages []Message
+	if err := s.requestJSON(ctx, "GET", url, nil, &messages, opts...); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (s *Session) PinMessage(ctx context.Context, channelID string, messageID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/pins/%s", apiBase, channelID, messageID)
+	return s.requestJSON(ctx, "PUT", url, nil, nil, opts...)
+}
+
+func (s *Session) UnpinMessage(ctx context.Context, channelID string, messageID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/pins/%s", apiBase, channelID, messageID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
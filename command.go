@@ -0,0 +1,185 @@
+// Handles application command registration
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+)
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-types
+const (
+	CommandTypeChatInput         = 1 // Slash commands
+	CommandTypeUser              = 2 // Right click on a user
+	CommandTypeMessage           = 3 // Right click on a message
+	CommandTypePrimaryEntryPoint = 4
+)
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-option-type
+const (
+	OptionTypeSubCommand      = 1
+	OptionTypeSubCommandGroup = 2
+	OptionTypeString          = 3
+	OptionTypeInteger         = 4
+	OptionTypeBoolean         = 5
+	OptionTypeUser            = 6
+	OptionTypeChannel         = 7
+	OptionTypeRole            = 8
+	OptionTypeMentionable     = 9
+	OptionTypeNumber          = 10
+	OptionTypeAttachment      = 11
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-interaction-context-types
+const (
+	ContextGuild          = 0
+	ContextBotDM          = 1
+	ContextPrivateChannel = 2
+)
+
+// https://discord.com/developers/docs/resources/application#application-object-application-integration-types
+const (
+	IntegrationTypeGuildInstall = 0
+	IntegrationTypeUserInstall  = 1
+)
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object
+type ApplicationCommand struct {
+	ID                       string            `json:"id,omitempty"`
+	Type                     int               `json:"type,omitempty"` // defaults to CommandTypeChatInput
+	ApplicationID            string            `json:"application_id,omitempty"`
+	GuildID                  string            `json:"guild_id,omitempty"`
+	Name                     string            `json:"name"`
+	NameLocalizations        map[string]string `json:"name_localizations,omitempty"`
+	Description              string            `json:"description,omitempty"` // required for slash commands
+	DescriptionLocalizations map[string]string `json:"description_localizations,omitempty"`
+	Options                  []*CommandOption  `json:"options,omitempty"`
+	// Permissions a member needs to use the command, 0 hides it from everyone but admins
+	DefaultMemberPermissions *Permissions `json:"default_member_permissions,omitempty"`
+	NSFW                     bool         `json:"nsfw,omitempty"`
+	IntegrationTypes         []int        `json:"integration_types,omitempty"`
+	Contexts                 []int        `json:"contexts,omitempty"`
+	Version                  string       `json:"version,omitempty"`
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-option-structure
+type CommandOption struct {
+	Type                     int               `json:"type"`
+	Name                     string            `json:"name"`
+	NameLocalizations        map[string]string `json:"name_localizations,omitempty"`
+	Description              string            `json:"description"`
+	DescriptionLocalizations map[string]string `json:"description_localizations,omitempty"`
+	Required                 bool              `json:"required,omitempty"`
+	Choices                  []*CommandChoice  `json:"choices,omitempty"`
+	Options                  []*CommandOption  `json:"options,omitempty"` // for subcommands and groups
+	ChannelTypes             []int             `json:"channel_types,omitempty"`
+	MinValue                 *float64          `json:"min_value,omitempty"`
+	MaxValue                 *float64          `json:"max_value,omitempty"`
+	MinLength                *int              `json:"min_length,omitempty"`
+	MaxLength                *int              `json:"max_length,omitempty"`
+	Autocomplete             bool              `json:"autocomplete,omitempty"`
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-option-choice-structure
+// Value is a string, integer or number depending on the option type.
+type CommandChoice struct {
+	Name              string            `json:"name"`
+	NameLocalizations map[string]string `json:"name_localizations,omitempty"`
+	Value             interface{}       `json:"value"`
+}
+
+// Returns the commands URL for a guild, or the global commands URL if guildID is empty
+func (s *Session) commandsURL(guildID string) string {
+	if guildID == "" {
+		return fmt.Sprintf("%s/applications/%s/commands", apiBase, s.ApplicationID)
+	}
+	return fmt.Sprintf("%s/applications/%s/guilds/%s/commands", apiBase, s.ApplicationID, guildID)
+}
+
+func (s *Session) listCommands(ctx context.Context, guildID string, opts []RequestOption) ([]*ApplicationCommand, error) {
+	var commands []*ApplicationCommand
+	if err := s.requestJSON(ctx, "GET", s.commandsURL(guildID)+"?with_localizations=true", nil, &commands, opts...); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+func (s *Session) commandRequest(ctx context.Context, method string, url string, data *ApplicationCommand, opts []RequestOption) (*ApplicationCommand, error) {
+	// Don't send a null body for GET requests
+	var body interface{}
+	if data != nil {
+		body = data
+	}
+	var command ApplicationCommand
+	if err := s.requestJSON(ctx, method, url, body, &command, opts...); err != nil {
+		return nil, err
+	}
+	return &command, nil
+}
+
+func (s *Session) GlobalCommands(ctx context.Context, opts ...RequestOption) ([]*ApplicationCommand, error) {
+	return s.listCommands(ctx, "", opts)
+}
+
+func (s *Session) GetGlobalCommand(ctx context.Context, commandID string, opts ...RequestOption) (*ApplicationCommand, error) {
+	return s.commandRequest(ctx, "GET", s.commandsURL("")+"/"+commandID, nil, opts)
+}
+
+// Creates a global command, or replaces the existing command with the same name
+func (s *Session) CreateGlobalCommand(ctx context.Context, command *ApplicationCommand, opts ...RequestOption) (*ApplicationCommand, error) {
+	return s.commandRequest(ctx, "POST", s.commandsURL(""), command, opts)
+}
+
+func (s *Session) EditGlobalCommand(ctx context.Context, commandID string, command *ApplicationCommand, opts ...RequestOption) (*ApplicationCommand, error) {
+	return s.commandRequest(ctx, "PATCH", s.commandsURL("")+"/"+commandID, command, opts)
+}
+
+func (s *Session) DeleteGlobalCommand(ctx context.Context, commandID string, opts ...RequestOption) error {
+	return s.requestJSON(ctx, "DELETE", s.commandsURL("")+"/"+commandID, nil, nil, opts...)
+}
+
+// Replaces all global commands, commands not in the list are deleted
+func (s *Session) BulkOverwriteGlobalCommands(ctx context.Context, commands []*ApplicationCommand, opts ...RequestOption) ([]*ApplicationCommand, error) {
+	if commands == nil {
+		// An empty list deletes all commands, null is rejected
+		commands = []*ApplicationCommand{}
+	}
+	var created []*ApplicationCommand
+	if err := s.requestJSON(ctx, "PUT", s.commandsURL(""), commands, &created, opts...); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *Session) GuildCommands(ctx context.Context, guildID string, opts ...RequestOption) ([]*ApplicationCommand, error) {
+	return s.listCommands(ctx, guildID, opts)
+}
+
+func (s *Session) GetGuildCommand(ctx context.Context, guildID string, commandID string, opts ...RequestOption) (*ApplicationCommand, error) {
+	return s.commandRequest(ctx, "GET", s.commandsURL(guildID)+"/"+commandID, nil, opts)
+}
+
+// Creates a guild command, or replaces the existing command with the same name
+func (s *Session) CreateGuildCommand(ctx context.Context, guildID string, command *ApplicationCommand, opts ...RequestOption) (*ApplicationCommand, error) {
+	return s.commandRequest(ctx, "POST", s.commandsURL(guildID), command, opts)
+}
+
+func (s *Session) EditGuildCommand(ctx context.Context, guildID string, commandID string, command *ApplicationCommand, opts ...RequestOption) (*ApplicationCommand, error) {
+	return s.commandRequest(ctx, "PATCH", s.commandsURL(guildID)+"/"+commandID, command, opts)
+}
+
+func (s *Session) DeleteGuildCommand(ctx context.Context, guildID string, commandID string, opts ...RequestOption) error {
+	return s.requestJSON(ctx, "DELETE", s.commandsURL(guildID)+"/"+commandID, nil, nil, opts...)
+}
+
+// Replaces all commands of a guild, commands not in the list are deleted
+func (s *Session) BulkOverwriteGuildCommands(ctx context.Context, guildID string, commands []*ApplicationCommand, opts ...RequestOption) ([]*ApplicationCommand, error) {
+	if commands == nil {
+		// An empty list deletes all commands, null is rejected
+		commands = []*ApplicationCommand{}
+	}
+	var created []*ApplicationCommand
+	if err := s.requestJSON(ctx, "PUT", s.commandsURL(guildID), commands, &created, opts...); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
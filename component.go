@@ -0,0 +1,438 @@
+// Handles message components
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// https://discord.com/developers/docs/components/reference#component-object-component-types
+const (
+	ComponentTypeActionRow         = 1
+	ComponentTypeButton            = 2
+	ComponentTypeStringSelect      = 3
+	ComponentTypeTextInput         = 4
+	ComponentTypeUserSelect        = 5
+	ComponentTypeRoleSelect        = 6
+	ComponentTypeMentionableSelect = 7
+	ComponentTypeChannelSelect     = 8
+	ComponentTypeSection           = 9
+	ComponentTypeTextDisplay       = 10
+	ComponentTypeThumbnail         = 11
+	ComponentTypeMediaGallery      = 12
+	ComponentTypeFile              = 13
+	ComponentTypeSeparator         = 14
+	ComponentTypeContainer         = 17
+)
+
+// https://discord.com/developers/docs/components/reference#button-button-styles
+const (
+	ButtonStylePrimary   = 1
+	ButtonStyleSecondary = 2
+	ButtonStyleSuccess   = 3
+	ButtonStyleDanger    = 4
+	ButtonStyleLink      = 5 // Opens URL, doesn't send an interaction
+	ButtonStylePremium   = 6 // Opens the purchase of SKUID, doesn't send an interaction
+)
+
+// Adds the type field to the JSON of a component
+func marshalComponent(component interface{}, componentType int) ([]byte, error) {
+	data, err := json.Marshal(component)
+	if err != nil {
+		return nil, err
+	}
+	// Every component marshals to an object, so the type can be spliced in front
+	prefix := fmt.Sprintf(`{"type":%d`, componentType)
+	if string(data) != "{}" {
+		prefix += ","
+	}
+	return append([]byte(prefix), data[1:]...), nil
+}
+
+// https://discord.com/developers/docs/components/reference#action-row
+// Holds up to 5 buttons or a single select menu.
+type ActionRow struct {
+	ID         int         `json:"id,omitempty"`
+	Components []Component `json:"components"`
+}
+
+func (ActionRow) ComponentType() int { return ComponentTypeActionRow }
+
+func (r ActionRow) MarshalJSON() ([]byte, error) {
+	type actionRow ActionRow
+	return marshalComponent(actionRow(r), r.ComponentType())
+}
+
+func NewActionRow(components ...Component) ActionRow {
+	return ActionRow{Components: components}
+}
+
+// https://discord.com/developers/docs/components/reference#button
+type Button struct {
+	ID       int    `json:"id,omitempty"`
+	Style    int    `json:"style"`
+	Label    string `json:"label,omitempty"`
+	Emoji    *Emoji `json:"emoji,omitempty"`
+	CustomID string `json:"custom_id,omitempty"` // not used by link and premium buttons
+	SKUID    string `json:"sku_id,omitempty"`    // only used by premium buttons
+	URL      string `json:"url,omitempty"`       // only used by link buttons
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+func (Button) ComponentType() int { return ComponentTypeButton }
+
+func (b Button) MarshalJSON() ([]byte, error) {
+	type button Button
+	return marshalComponent(button(b), b.ComponentType())
+}
+
+func NewButton(style int, label string, customID string) Button {
+	return Button{Style: style, Label: label, CustomID: customID}
+}
+
+func NewLinkButton(label string, url string) Button {
+	return Button{Style: ButtonStyleLink, Label: label, URL: url}
+}
+
+// Premium buttons have no label, Discord shows the name and price of the SKU
+func NewPremiumButton(skuID string) Button {
+	return Button{Style: ButtonStylePremium, SKUID: skuID}
+}
+
+// https://discord.com/developers/docs/components/reference#string-select
+// Used for all select menus, MenuType is one of the ComponentType*Select constants.
+type SelectMenu struct {
+	ID            int                  `json:"id,omitempty"`
+	MenuType      int                  `json:"-"` // defaults to ComponentTypeStringSelect
+	CustomID      string               `json:"custom_id"`
+	Options       []SelectOption       `json:"options,omitempty"`       // only used by string selects
+	ChannelTypes  []int                `json:"channel_types,omitempty"` // only used by channel selects
+	Placeholder   string               `json:"placeholder,omitempty"`
+	DefaultValues []SelectDefaultValue `json:"default_values,omitempty"` // not used by string selects
+	MinValues     *int                 `json:"min_values,omitempty"`
+	MaxValues     int                  `json:"max_values,omitempty"`
+	Required      *bool                `json:"required,omitempty"` // only used in modals
+	Disabled      bool                 `json:"disabled,omitempty"`
+}
+
+func (m SelectMenu) ComponentType() int {
+	if m.MenuType == 0 {
+		return ComponentTypeStringSelect
+	}
+	return m.MenuType
+}
+
+func (m SelectMenu) MarshalJSON() ([]byte, error) {
+	type selectMenu SelectMenu
+	return marshalComponent(selectMenu(m), m.ComponentType())
+}
+
+// https://discord.com/developers/docs/components/reference#string-select-select-option-structure
+type SelectOption struct {
+	Label       string `json:"label"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Emoji       *Emoji `json:"emoji,omitempty"`
+	Default     bool   `json:"default,omitempty"`
+}
+
+// https://discord.com/developers/docs/components/reference#user-select-select-default-value-structure
+type SelectDefaultValue struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "user", "role" or "channel"
+}
+
+func NewStringSelect(customID string, options ...SelectOption) SelectMenu {
+	return SelectMenu{MenuType: ComponentTypeStringSelect, CustomID: customID, Options: options}
+}
+
+func NewUserSelect(customID string) SelectMenu {
+	return SelectMenu{MenuType: ComponentTypeUserSelect, CustomID: customID}
+}
+
+func NewRoleSelect(customID string) SelectMenu {
+	return SelectMenu{MenuType: ComponentTypeRoleSelect, CustomID: customID}
+}
+
+// Lets the user pick users and roles
+func NewMentionableSelect(customID string) SelectMenu {
+	return SelectMenu{MenuType: ComponentTypeMentionableSelect, CustomID: customID}
+}
+
+// Lets the user pick channels, optionally only of the given types
+func NewChannelSelect(customID string, channelTypes ...int) SelectMenu {
+	return SelectMenu{MenuType: ComponentTypeChannelSelect, CustomID: customID, ChannelTypes: channelTypes}
+}
+
+// The components below are only shown if the message has MessageFlagIsComponentsV2,
+// which replaces content and embeds.
+
+// https://discord.com/developers/docs/components/reference#section
+// Shows 1 to 3 text displays next to an accessory, which is a thumbnail or a button.
+type Section struct {
+	ID         int         `json:"id,omitempty"`
+	Components []Component `json:"components"`
+	Accessory  Component   `json:"accessory"`
+}
+
+func (Section) ComponentType() int { return ComponentTypeSection }
+
+func (s Section) MarshalJSON() ([]byte, error) {
+	type section Section
+	return marshalComponent(section(s), s.ComponentType())
+}
+
+func NewSection(accessory Component, texts ...string) Section {
+	section := Section{Accessory: accessory}
+	for _, text := range texts {
+		section.Components = append(section.Components, NewTextDisplay(text))
+	}
+	return section
+}
+
+// https://discord.com/developers/docs/components/reference#text-display
+// Shows markdown text, like message content.
+type TextDisplay struct {
+	ID      int    `json:"id,omitempty"`
+	Content string `json:"content"`
+}
+
+func (TextDisplay) ComponentType() int { return ComponentTypeTextDisplay }
+
+func (t TextDisplay) MarshalJSON() ([]byte, error) {
+	type textDisplay TextDisplay
+	return marshalComponent(textDisplay(t), t.ComponentType())
+}
+
+func NewTextDisplay(content string) TextDisplay {
+	return TextDisplay{Content: content}
+}
+
+// https://discord.com/developers/docs/components/reference#unfurled-media-item-structure
+// URL can be a web URL or "attachment://<filename>" for an uploaded file.
+type UnfurledMediaItem struct {
+	URL string `json:"url"`
+}
+
+// https://discord.com/developers/docs/components/reference#thumbnail
+type Thumbnail struct {
+	ID          int               `json:"id,omitempty"`
+	Media       UnfurledMediaItem `json:"media"`
+	Description string            `json:"description,omitempty"`
+	Spoiler     bool              `json:"spoiler,omitempty"`
+}
+
+func (Thumbnail) ComponentType() int { return ComponentTypeThumbnail }
+
+func (t Thumbnail) MarshalJSON() ([]byte, error) {
+	type thumbnail Thumbnail
+	return marshalComponent(thumbnail(t), t.ComponentType())
+}
+
+func NewThumbnail(url string) Thumbnail {
+	return Thumbnail{Media: UnfurledMediaItem{URL: url}}
+}
+
+// https://discord.com/developers/docs/components/reference#media-gallery
+type MediaGallery struct {
+	ID    int                `json:"id,omitempty"`
+	Items []MediaGalleryItem `json:"items"`
+}
+
+// https://discord.com/developers/docs/components/reference#media-gallery-media-gallery-item-structure
+type MediaGalleryItem struct {
+	Media       UnfurledMediaItem `json:"media"`
+	Description string            `json:"description,omitempty"`
+	Spoiler     bool              `json:"spoiler,omitempty"`
+}
+
+func (MediaGallery) ComponentType() int { return ComponentTypeMediaGallery }
+
+func (g MediaGallery) MarshalJSON() ([]byte, error) {
+	type mediaGallery MediaGallery
+	return marshalComponent(mediaGallery(g), g.ComponentType())
+}
+
+// Creates a gallery of 1 to 10 images or videos
+func NewMediaGallery(urls ...string) MediaGallery {
+	var gallery MediaGallery
+	for _, url := range urls {
+		gallery.Items = append(gallery.Items, MediaGalleryItem{Media: UnfurledMediaItem{URL: url}})
+	}
+	return gallery
+}
+
+// https://discord.com/developers/docs/components/reference#file
+// Shows an uploaded file, File must be an "attachment://<filename>" URL.
+type FileComponent struct {
+	ID      int               `json:"id,omitempty"`
+	File    UnfurledMediaItem `json:"file"`
+	Spoiler bool              `json:"spoiler,omitempty"`
+}
+
+func (FileComponent) ComponentType() int { return ComponentTypeFile }
+
+func (f FileComponent) MarshalJSON() ([]byte, error) {
+	type fileComponent FileComponent
+	return marshalComponent(fileComponent(f), f.ComponentType())
+}
+
+// https://discord.com/developers/docs/components/reference#separator
+type Separator struct {
+	ID      int   `json:"id,omitempty"`
+	Divider *bool `json:"divider,omitempty"` // whether a line is shown, defaults to true
+	Spacing int   `json:"spacing,omitempty"` // 1 for small, 2 for large padding
+}
+
+func (Separator) ComponentType() int { return ComponentTypeSeparator }
+
+func (s Separator) MarshalJSON() ([]byte, error) {
+	type separator Separator
+	return marshalComponent(separator(s), s.ComponentType())
+}
+
+// https://discord.com/developers/docs/components/reference#container
+// Groups components in a box, like an embed.
+type Container struct {
+	ID          int         `json:"id,omitempty"`
+	Components  []Component `json:"components"`
+	AccentColor *int        `json:"accent_color,omitempty"`
+	Spoiler     bool        `json:"spoiler,omitempty"`
+}
+
+func (Container) ComponentType() int { return ComponentTypeContainer }
+
+func (c Container) MarshalJSON() ([]byte, error) {
+	type container Container
+	return marshalComponent(container(c), c.ComponentType())
+}
+
+func NewContainer(components ...Component) Container {
+	return Container{Components: components}
+}
+
+// Returns a copy of components with all buttons and select menus disabled,
+// including those nested in action rows, sections and containers.
+// Link and premium buttons are kept enabled since they don't expire.
+func DisableComponents(components []Component) []Component {
+	disabled := make([]Component, 0, len(components))
+	for _, c := range components {
+		disabled = append(disabled, disableComponent(c))
+	}
+	return disabled
+}
+
+func disableComponent(c Component) Component {
+	switch v := c.(type) {
+	case *ActionRow:
+		return disableComponent(*v)
+	case *Button:
+		return disableComponent(*v)
+	case *SelectMenu:
+		return disableComponent(*v)
+	case *Section:
+		return disableComponent(*v)
+	case *Container:
+		return disableComponent(*v)
+	case ActionRow:
+		v.Components = DisableComponents(v.Components)
+		return v
+	case Button:
+		if v.Style != ButtonStyleLink && v.Style != ButtonStylePremium {
+			v.Disabled = true
+		}
+		return v
+	case SelectMenu:
+		v.Disabled = true
+		return v
+	case Section:
+		if v.Accessory != nil {
+			v.Accessory = disableComponent(v.Accessory)
+		}
+		return v
+	case Container:
+		v.Components = DisableComponents(v.Components)
+		return v
+	}
+	return c
+}
+
+// Disables components after a period of inactivity, see DisableComponentsAfter
+type ComponentTimeout struct {
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+// Restarts the timeout, e.g. when one of the components is used
+func (t *ComponentTimeout) Reset() {
+	t.timer.Reset(t.timeout)
+}
+
+// Keeps the components enabled, returns false if they were already disabled
+func (t *ComponentTimeout) Stop() bool {
+	return t.timer.Stop()
+}
+
+func newComponentTimeout(timeout time.Duration, disable func(ctx context.Context) error) *ComponentTimeout {
+	return &ComponentTimeout{
+		timeout: timeout,
+		timer: time.AfterFunc(timeout, func() {
+			if err := disable(context.Background()); err != nil {
+				log.Printf("[Components] Failed to disable components: %v\n", err)
+			}
+		}),
+	}
+}
+
+// Disables the components of a message once timeout passes, unless stopped first.
+// components must be the components the message was sent with.
+func (s *Session) DisableComponentsAfter(channelID string, messageID string, components []Component, timeout time.Duration) *ComponentTimeout {
+	return newComponentTimeout(timeout, func(ctx context.Context) error {
+		disabled := DisableComponents(components)
+		_, err := s.EditMessage(ctx, channelID, messageID, MessageEdit{Components: &disabled})
+		return err
+	})
+}
+
+// Like DisableComponentsAfter for the original response of an interaction, which also
+// works for ephemeral messages. The timeout must be below 15 minutes, when the token expires.
+func (s *Session) DisableResponseComponentsAfter(i *Interaction, components []Component, timeout time.Duration) *ComponentTimeout {
+	return newComponentTimeout(timeout, func(ctx context.Context) error {
+		disabled := DisableComponents(components)
+		_, err := s.EditOriginalResponse(ctx, i, MessageEdit{Components: &disabled})
+		return err
+	})
+}
+
+// Separates the prefix and parameters of a custom ID
+const customIDSeparator = ":"
+
+// Builds a custom ID that the router dispatches to the handler for prefix,
+// e.g. CustomID("vote", "yes", pollID) gives "vote:yes:<pollID>". Custom IDs are at most 100 characters.
+func CustomID(prefix string, params ...string) string {
+	return strings.Join(append([]string{prefix}, params...), customIDSeparator)
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-message-component-data-structure
+type MessageComponentData struct {
+	CustomID      string        `json:"custom_id"`
+	ComponentType int           `json:"component_type"`
+	Values        []string      `json:"values,omitempty"` // selected values of select menus
+	Resolved      *ResolvedData `json:"resolved,omitempty"`
+}
+
+// Returns the data of a message component interaction
+func (i *Interaction) ComponentData() (*MessageComponentData, error) {
+	if i.Type != InteractionTypeMessageComponent {
+		return nil, fmt.Errorf("interaction type %d is not a component", i.Type)
+	}
+	var data MessageComponentData
+	if err := json.Unmarshal(i.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,100 @@
+// Handles context menu commands, shown when right clicking a user or message
+package discordgowrap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Passed to user command handlers
+type UserCommandContext struct {
+	*CommandContext
+	User   *User
+	Member *Member // nil outside of guilds
+}
+
+// Passed to message command handlers
+type MessageCommandContext struct {
+	*CommandContext
+	Message *Message
+}
+
+// Registers a command shown under Apps when right clicking a user. Unlike slash
+// commands the name can contain spaces and capital letters, e.g. "Show Avatar".
+func (r *Router) UserCommand(name string, handler func(c *UserCommandContext) error, middleware ...Middleware) {
+	r.addContextMenu(CommandTypeUser, name, func(c *CommandContext) error {
+		resolved := c.Data.Resolved
+		if resolved == nil {
+			return fmt.Errorf("user command %s has no resolved target", name)
+		}
+		user, exists := resolved.Users[c.Data.TargetID]
+		if !exists {
+			return fmt.Errorf("user command %s has no resolved user %s", name, c.Data.TargetID)
+		}
+		uc := &UserCommandContext{CommandContext: c, User: &user}
+		if member, exists := resolved.Members[c.Data.TargetID]; exists {
+			member.User = &user
+			member.GuildID = c.Interaction.GuildID
+			uc.Member = &member
+		}
+		return handler(uc)
+	}, middleware)
+}
+
+// Registers a command shown under Apps when right clicking a message
+func (r *Router) MessageCommand(name string, handler func(c *MessageCommandContext) error, middleware ...Middleware) {
+	r.addContextMenu(CommandTypeMessage, name, func(c *CommandContext) error {
+		if c.Data.Resolved == nil {
+			return fmt.Errorf("message command %s has no resolved target", name)
+		}
+		message, exists := c.Data.Resolved.Messages[c.Data.TargetID]
+		if !exists {
+			return fmt.Errorf("message command %s has no resolved message %s", name, c.Data.TargetID)
+		}
+		return handler(&MessageCommandContext{CommandContext: c, Message: &message})
+	}, middleware)
+}
+
+// Sets the permissions members need to see a user command
+func (r *Router) SetUserCommandPermissions(name string, permissions Permissions) {
+	r.setPermissions(CommandTypeUser, name, permissions)
+}
+
+// Sets the permissions members need to see a message command
+func (r *Router) SetMessageCommandPermissions(name string, permissions Permissions) {
+	r.setPermissions(CommandTypeMessage, name, permissions)
+}
+
+func (r *Router) addContextMenu(commandType int, name string, handler CommandHandler, middleware []Middleware) {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.contextMenus[commandKey{commandType, name}] = handler
+}
+
+// Returns the definitions of the context menu commands, the caller must hold the lock
+func (r *Router) contextMenuCommands() []*ApplicationCommand {
+	keys := make([]commandKey, 0, len(r.contextMenus))
+	for key := range r.contextMenus {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b commandKey) int {
+		if a.commandType != b.commandType {
+			return a.commandType - b.commandType
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	commands := make([]*ApplicationCommand, 0, len(keys))
+	for _, key := range keys {
+		cmd := &ApplicationCommand{Type: key.commandType, Name: key.name}
+		if perms, exists := r.permissions[key]; exists {
+			cmd.DefaultMemberPermissions = &perms
+		}
+		commands = append(commands, cmd)
+	}
+	return commands
+}
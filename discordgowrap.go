@@ -1,8 +1,10 @@
 package discordgowrap
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"runtime"
@@ -10,7 +12,7 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-func New(token string, intents int) (*Session, error) {
+func New(token string, intents int, options ...IdentifyOption) (*Session, error) {
 	dialer := websocket.DefaultDialer
 	conn, _, err := dialer.Dial(gateway, nil)
 
@@ -19,18 +21,22 @@ func New(token string, intents int) (*Session, error) {
 	}
 
 	// Identifies and connects the bot
-	identify := GatewayPayload{
-		Op: OpIdentify,
-		Data: Identify{
-			Token:   token,
-			Intents: intents,
-			Properties: IdentifyProperties{
-				OS:      runtime.GOOS,
-				Browser: "discordgowrap (https://github.com/skarkii/discordgowrap)",
-				Device:  "discordgowrap (https://github.com/skarkii/discordgowrap)",
-			},
+	identifyData := Identify{
+		Token:   token,
+		Intents: intents,
+		Properties: IdentifyProperties{
+			OS:      runtime.GOOS,
+			Browser: "discordgowrap (https://github.com/skarkii/discordgowrap)",
+			Device:  "discordgowrap (https://github.com/skarkii/discordgowrap)",
 		},
 	}
+	for _, option := range options {
+		option(&identifyData)
+	}
+	identify := GatewayPayload{
+		Op:   OpIdentify,
+		Data: identifyData,
+	}
 	if err := conn.WriteJSON(identify); err != nil {
 		log.Printf("error sending IDENTIFY: %v\n", err)
 	}
@@ -61,15 +67,45 @@ func New(token string, intents int) (*Session, error) {
 		log.Printf("Error unmarshaling message: %v\n", err)
 	}
 	s := Session{
-		token,
-		conn,
-		intents,
-		bot{ID: msg.User.ID, Name: msg.User.Name},
-		&http.Client{},
-		make(map[string]*voiceConnection),
+		Token:            token,
+		conn:             conn,
+		intents:          intents,
+		Bot:              bot{ID: msg.User.ID, Name: msg.User.Name},
+		ApplicationID:    msg.Application.ID,
+		httpClient:       &http.Client{},
+		rateLimiter:      newRateLimiter(),
+		voiceConnections: make(map[string]*voiceConnection),
+		ready:            &msg,
 	}
 	//fmt.Printf("Retrieved Ack from Identify, starting heartbeat\n")
 	go startHeartbeat(s.conn, heartbeatInterval)
 
 	return &s, nil
 }
+
+// Returned by gateway methods of a session created with NewREST
+var ErrNoGateway = errors.New("session has no gateway connection")
+
+// Creates a session that only uses the REST API, without connecting to the gateway.
+// Use it to receive interactions over HTTP with Router.HTTPHandler. The bot user and
+// application ID are fetched from the API.
+func NewREST(token string) (*Session, error) {
+	s := &Session{
+		Token:            token,
+		httpClient:       &http.Client{},
+		rateLimiter:      newRateLimiter(),
+		voiceConnections: make(map[string]*voiceConnection),
+	}
+
+	var app struct {
+		ID  string `json:"id"`
+		Bot User   `json:"bot"`
+	}
+	url := fmt.Sprintf("%s/applications/@me", apiBase)
+	if err := s.requestJSON(context.Background(), "GET", url, nil, &app); err != nil {
+		return nil, fmt.Errorf("error fetching application: %v", err)
+	}
+	s.ApplicationID = app.ID
+	s.Bot = bot{ID: app.Bot.ID, Name: app.Bot.Name}
+	return s, nil
+}
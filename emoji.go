@@ -0,0 +1,73 @@
+// Handles emojis
+package discordgowrap
+
+import (
+	"net/url"
+	"strings"
+)
+
+// https://discord.com/developers/docs/resources/emoji#emoji-object
+// Unicode emojis only have a Name, custom emojis have both a Name and an ID.
+type Emoji struct {
+	ID            string   `json:"id,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+	User          *User    `json:"user,omitempty"`
+	RequireColons bool     `json:"require_colons,omitempty"`
+	Managed       bool     `json:"managed,omitempty"`
+	Animated      bool     `json:"animated,omitempty"`
+	Available     bool     `json:"available,omitempty"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#guild-emojis-update
+// Emojis replaces all emojis of the guild.
+type GuildEmojisUpdate struct {
+	GuildID string  `json:"guild_id"`
+	Emojis  []Emoji `json:"emojis"`
+}
+
+func (*GuildEmojisUpdate) EventType() string { return TypeGuildEmojisUpdate }
+
+// Parses a unicode emoji ("👍"), a custom emoji as written in a message
+// ("<:name:id>" or "<a:name:id>") or a custom emoji in API form ("name:id").
+func ParseEmoji(s string) Emoji {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+		inner := strings.TrimSuffix(strings.TrimPrefix(s, "<"), ">")
+		animated := strings.HasPrefix(inner, "a:")
+		inner = strings.TrimPrefix(inner, "a:")
+		inner = strings.TrimPrefix(inner, ":")
+		if name, id, ok := strings.Cut(inner, ":"); ok {
+			return Emoji{ID: id, Name: name, Animated: animated}
+		}
+	}
+	if name, id, ok := strings.Cut(s, ":"); ok && isSnowflake(id) {
+		return Emoji{ID: id, Name: name}
+	}
+	return Emoji{Name: s}
+}
+
+// Returns the emoji as used in REST routes, "name:id" for custom emojis
+// and the unicode character itself otherwise
+func (e Emoji) APIName() string {
+	if e.ID != "" {
+		return e.Name + ":" + e.ID
+	}
+	return e.Name
+}
+
+// Returns the emoji URL encoded for use in a REST route
+func (e Emoji) URLEncoded() string {
+	return url.PathEscape(e.APIName())
+}
+
+// Returns the string that renders the emoji in a message
+func (e Emoji) MessageFormat() string {
+	if e.ID == "" {
+		return e.Name
+	}
+	if e.Animated {
+		return "<a:" + e.Name + ":" + e.ID + ">"
+	}
+	return "<:" + e.Name + ":" + e.ID + ">"
+}
@@ -0,0 +1,73 @@
+package discordgowrap
+
+import "testing"
+
+func TestParseEmoji(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       Emoji
+		apiName    string
+		urlEncoded string
+		message    string
+	}{
+		{
+			name:       "unicode",
+			input:      "👍",
+			want:       Emoji{Name: "👍"},
+			apiName:    "👍",
+			urlEncoded: "%F0%9F%91%8D",
+			message:    "👍",
+		},
+		{
+			name:       "unicode with skin tone modifier",
+			input:      "👍🏽",
+			want:       Emoji{Name: "👍🏽"},
+			apiName:    "👍🏽",
+			urlEncoded: "%F0%9F%91%8D%F0%9F%8F%BD",
+			message:    "👍🏽",
+		},
+		{
+			name:       "api form",
+			input:      "blob:123456789012345678",
+			want:       Emoji{ID: "123456789012345678", Name: "blob"},
+			apiName:    "blob:123456789012345678",
+			urlEncoded: "blob:123456789012345678",
+			message:    "<:blob:123456789012345678>",
+		},
+		{
+			name:       "message form",
+			input:      "<:blob:123456789012345678>",
+			want:       Emoji{ID: "123456789012345678", Name: "blob"},
+			apiName:    "blob:123456789012345678",
+			urlEncoded: "blob:123456789012345678",
+			message:    "<:blob:123456789012345678>",
+		},
+		{
+			name:       "animated message form",
+			input:      " <a:dance:123456789012345678> ",
+			want:       Emoji{ID: "123456789012345678", Name: "dance", Animated: true},
+			apiName:    "dance:123456789012345678",
+			urlEncoded: "dance:123456789012345678",
+			message:    "<a:dance:123456789012345678>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseEmoji(tt.input)
+			if got.ID != tt.want.ID || got.Name != tt.want.Name || got.Animated != tt.want.Animated {
+				t.Fatalf("ParseEmoji(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+			if name := got.APIName(); name != tt.apiName {
+				t.Errorf("APIName() = %q, want %q", name, tt.apiName)
+			}
+			if encoded := got.URLEncoded(); encoded != tt.urlEncoded {
+				t.Errorf("URLEncoded() = %q, want %q", encoded, tt.urlEncoded)
+			}
+			if message := got.MessageFormat(); message != tt.message {
+				t.Errorf("MessageFormat() = %q, want %q", message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Handles errors returned by the REST API
+package discordgowrap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#json-json-error-codes
+const (
+	ErrCodeGeneral                  = 0
+	ErrCodeUnknownAccount           = 10001
+	ErrCodeUnknownApplication       = 10002
+	ErrCodeUnknownChannel           = 10003
+	ErrCodeUnknownGuild             = 10004
+	ErrCodeUnknownIntegration       = 10005
+	ErrCodeUnknownInvite            = 10006
+	ErrCodeUnknownMember            = 10007
+	ErrCodeUnknownMessage           = 10008
+	ErrCodeUnknownOverwrite         = 10009
+	ErrCodeUnknownRole              = 10011
+	ErrCodeUnknownToken             = 10012
+	ErrCodeUnknownUser              = 10013
+	ErrCodeUnknownEmoji             = 10014
+	ErrCodeUnknownWebhook           = 10015
+	ErrCodeUnknownBan               = 10026
+	ErrCodeUnknownInteraction       = 10062
+	ErrCodeUnknownApplicationCmd    = 10063
+	ErrCodeMaxGuilds                = 30001
+	ErrCodeMaxReactions             = 30010
+	ErrCodeUnauthorized             = 40001
+	ErrCodeInteractionAcknowledged  = 40060
+	ErrCodeMissingAccess            = 50001
+	ErrCodeInvalidAccountType       = 50002
+	ErrCodeCannotExecuteOnDM        = 50003
+	ErrCodeCannotEditOthersMessage  = 50005
+	ErrCodeCannotSendEmptyMessage   = 50006
+	ErrCodeCannotSendMessagesToUser = 50007
+	ErrCodeMissingPermissions       = 50013
+	ErrCodeInvalidToken             = 50014
+	ErrCodeMessageTooOldToBulk      = 50034
+	ErrCodeInvalidFormBody          = 50035
+	ErrCodeReactionBlocked          = 90001
+)
+
+// Returned by REST calls when Discord responds with a non 2xx status
+type RESTError struct {
+	Status  int        // HTTP status code
+	Code    int        // Discord JSON error code, 0 if none was sent
+	Message string     // Discord error message
+	Errors  *ErrorTree // Form validation errors, nil if none were sent
+	Body    []byte     // Raw response body
+}
+
+// A single validation error for a field
+type FieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Nested validation errors as sent in the "errors" field, e.g.
+// {"embeds": {"0": {"title": {"_errors": [...]}}}}
+type ErrorTree struct {
+	Errors   []FieldError
+	Children map[string]*ErrorTree
+}
+
+func (t *ErrorTree) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for key, value := range raw {
+		if key == "_errors" {
+			if err := json.Unmarshal(value, &t.Errors); err != nil {
+				return err
+			}
+			continue
+		}
+		child := &ErrorTree{}
+		if err := json.Unmarshal(value, child); err != nil {
+			return err
+		}
+		if t.Children == nil {
+			t.Children = make(map[string]*ErrorTree)
+		}
+		t.Children[key] = child
+	}
+	return nil
+}
+
+// Returns the validation errors keyed by their dotted field path, e.g. "embeds.0.title"
+func (t *ErrorTree) Flatten() map[string][]FieldError {
+	fields := make(map[string][]FieldError)
+	t.flatten("", fields)
+	return fields
+}
+
+func (t *ErrorTree) flatten(prefix string, fields map[string][]FieldError) {
+	if len(t.Errors) > 0 {
+		fields[prefix] = t.Errors
+	}
+	for key, child := range t.Children {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		child.flatten(path, fields)
+	}
+}
+
+func newRESTError(status int, body []byte) *RESTError {
+	e := &RESTError{Status: status, Body: body}
+	var data struct {
+		Code    int        `json:"code"`
+		Message string     `json:"message"`
+		Errors  *ErrorTree `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &data); err == nil {
+		e.Code = data.Code
+		e.Message = data.Message
+		e.Errors = data.Errors
+	}
+	return e
+}
+
+func (e *RESTError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "discord: %d %s", e.Status, http.StatusText(e.Status))
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s (%d)", e.Message, e.Code)
+	}
+	if e.Errors != nil {
+		fields := e.Errors.Flatten()
+		paths := make([]string, 0, len(fields))
+		for path := range fields {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			for _, fe := range fields[path] {
+				fmt.Fprintf(&sb, "; %s: %s", path, fe.Message)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Returns whether err is a RESTError with the given Discord error code
+func IsErrorCode(err error, code int) bool {
+	var restErr *RESTError
+	return errors.As(err, &restErr) && restErr.Code == code
+}
+
+// Returns whether err is a RESTError with the given HTTP status code
+func IsStatus(err error, status int) bool {
+	var restErr *RESTError
+	return errors.As(err, &restErr) && restErr.Status == status
+}
+
+func IsUnknownMessage(err error) bool {
+	return IsErrorCode(err, ErrCodeUnknownMessage)
+}
+
+func IsUnknownChannel(err error) bool {
+	return IsErrorCode(err, ErrCodeUnknownChannel)
+}
+
+func IsUnknownMember(err error) bool {
+	return IsErrorCode(err, ErrCodeUnknownMember)
+}
+
+func IsMissingAccess(err error) bool {
+	return IsErrorCode(err, ErrCodeMissingAccess)
+}
+
+func IsMissingPermissions(err error) bool {
+	return IsErrorCode(err, ErrCodeMissingPermissions)
+}
+
+func IsCannotDMUser(err error) bool {
+	return IsErrorCode(err, ErrCodeCannotSendMessagesToUser)
+}
+
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
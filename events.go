@@ -0,0 +1,60 @@
+// Handles typed gateway events
+package discordgowrap
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// A decoded gateway event, EventType returns one of the Type* constants
+type Event interface {
+	EventType() string
+}
+
+type eventHandlers struct {
+	sync.RWMutex
+	handlers map[string][]func(*Session, Event)
+}
+
+// Registers a handler that is called from GetMessage whenever the event is received.
+//
+//	discordgowrap.AddHandler(s, func(s *discordgowrap.Session, e *discordgowrap.MessageReactionAdd) {
+//		...
+//	})
+func AddHandler[E Event](s *Session, handler func(*Session, E)) {
+	// Events use pointer receivers, so the zero value is a nil pointer that still knows its type
+	var zero E
+	eventType := zero.EventType()
+
+	s.eventHandlers.Lock()
+	defer s.eventHandlers.Unlock()
+	if s.eventHandlers.handlers == nil {
+		s.eventHandlers.handlers = make(map[string][]func(*Session, Event))
+	}
+	s.eventHandlers.handlers[eventType] = append(s.eventHandlers.handlers[eventType], func(s *Session, e Event) {
+		handler(s, e.(E))
+	})
+}
+
+func (s *Session) dispatch(e Event) {
+	s.eventHandlers.RLock()
+	handlers := s.eventHandlers.handlers[e.EventType()]
+	s.eventHandlers.RUnlock()
+	for _, handler := range handlers {
+		handler(s, e)
+	}
+}
+
+// Decodes the payload data into the event and calls its handlers
+func handleEvent[E any, PE interface {
+	*E
+	Event
+}](s *Session, data interface{}) (PE, error) {
+	e := PE(new(E))
+	raw, _ := json.Marshal(data)
+	if err := json.Unmarshal(raw, e); err != nil {
+		return nil, err
+	}
+	s.dispatch(e)
+	return e, nil
+}
@@ -0,0 +1,417 @@
+// Handles guilds
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// https://discord.com/developers/docs/resources/guild#guild-object
+type Guild struct {
+	ID                          string   `json:"id"`
+	Name                        string   `json:"name"`
+	Icon                        string   `json:"icon,omitempty"`
+	Splash                      string   `json:"splash,omitempty"`
+	DiscoverySplash             string   `json:"discovery_splash,omitempty"`
+	OwnerID                     string   `json:"owner_id"`
+	AFKChannelID                string   `json:"afk_channel_id,omitempty"`
+	AFKTimeout                  int      `json:"afk_timeout"`
+	WidgetEnabled               bool     `json:"widget_enabled,omitempty"`
+	WidgetChannelID             string   `json:"widget_channel_id,omitempty"`
+	VerificationLevel           int      `json:"verification_level"`
+	DefaultMessageNotifications int      `json:"default_message_notifications"`
+	ExplicitContentFilter       int      `json:"explicit_content_filter"`
+	Roles                       []Role   `json:"roles"`
+	Emojis                      []Emoji  `json:"emojis"`
+	Features                    []string `json:"features"`
+	MFALevel                    int      `json:"mfa_level"`
+	ApplicationID               string   `json:"application_id,omitempty"`
+	SystemChannelID             string   `json:"system_channel_id,omitempty"`
+	SystemChannelFlags          int      `json:"system_channel_flags"`
+	RulesChannelID              string   `json:"rules_channel_id,omitempty"`
+	MaxPresences                int      `json:"max_presences,omitempty"`
+	MaxMembers                  int      `json:"max_members,omitempty"`
+	VanityURLCode               string   `json:"vanity_url_code,omitempty"`
+	Description                 string   `json:"description,omitempty"`
+	Banner                      string   `json:"banner,omitempty"`
+	PremiumTier                 int      `json:"premium_tier"`
+	PremiumSubscriptionCount    int      `json:"premium_subscription_count,omitempty"`
+	PreferredLocale             string   `json:"preferred_locale"`
+	PublicUpdatesChannelID      string   `json:"public_updates_channel_id,omitempty"`
+	MaxVideoChannelUsers        int      `json:"max_video_channel_users,omitempty"`
+	NSFWLevel                   int      `json:"nsfw_level"`
+	PremiumProgressBarEnabled   bool     `json:"premium_progress_bar_enabled"`
+	SafetyAlertsChannelID       string   `json:"safety_alerts_channel_id,omitempty"`
+
+	// Only set by GetGuild with counts
+	ApproximateMemberCount   int `json:"approximate_member_count,omitempty"`
+	ApproximatePresenceCount int `json:"approximate_presence_count,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#unavailable-guild-object
+type UnavailableGuild struct {
+	ID          string `json:"id"`
+	Unavailable bool   `json:"unavailable"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#guild-create
+// Sent for every guild after connecting, when a guild becomes available and when the bot joins one.
+type GuildCreate struct {
+	Guild
+	JoinedAt    time.Time    `json:"joined_at"`
+	Large       bool         `json:"large"` // more members than the identify large_threshold, Members is then incomplete
+	Unavailable bool         `json:"unavailable,omitempty"`
+	MemberCount int          `json:"member_count"`
+	Members     []Member     `json:"members"`
+	Channels    []Channel    `json:"channels"`
+	Threads     []Channel    `json:"threads"` // active threads the bot can see
+	VoiceStates []VoiceState `json:"voice_states"`
+}
+
+func (*GuildCreate) EventType() string { return TypeGuildCreate }
+
+// https://discord.com/developers/docs/events/gateway-events#guild-update
+type GuildUpdate struct {
+	Guild
+}
+
+func (*GuildUpdate) EventType() string { return TypeGuildUpdate }
+
+// https://discord.com/developers/docs/events/gateway-events#guild-delete
+// Unavailable is true during an outage, otherwise the bot left or was removed from the guild.
+type GuildDelete struct {
+	UnavailableGuild
+}
+
+func (*GuildDelete) EventType() string { return TypeGuildDelete }
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-json-params
+// Nil fields are left unchanged.
+type GuildEdit struct {
+	Name                        *string   `json:"name,omitempty"`
+	VerificationLevel           *int      `json:"verification_level,omitempty"`
+	DefaultMessageNotifications *int      `json:"default_message_notifications,omitempty"`
+	ExplicitContentFilter       *int      `json:"explicit_content_filter,omitempty"`
+	AFKChannelID                *string   `json:"afk_channel_id,omitempty"`
+	AFKTimeout                  *int      `json:"afk_timeout,omitempty"`
+	Icon                        *string   `json:"icon,omitempty"` // image data URI
+	Splash                      *string   `json:"splash,omitempty"`
+	DiscoverySplash             *string   `json:"discovery_splash,omitempty"`
+	Banner                      *string   `json:"banner,omitempty"`
+	SystemChannelID             *string   `json:"system_channel_id,omitempty"`
+	SystemChannelFlags          *int      `json:"system_channel_flags,omitempty"`
+	RulesChannelID              *string   `json:"rules_channel_id,omitempty"`
+	PublicUpdatesChannelID      *string   `json:"public_updates_channel_id,omitempty"`
+	PreferredLocale             *string   `json:"preferred_locale,omitempty"`
+	Features                    *[]string `json:"features,omitempty"`
+	Description                 *string   `json:"description,omitempty"`
+	PremiumProgressBarEnabled   *bool     `json:"premium_progress_bar_enabled,omitempty"`
+	SafetyAlertsChannelID       *string   `json:"safety_alerts_channel_id,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#guild-preview-object
+type GuildPreview struct {
+	ID                       string   `json:"id"`
+	Name                     string   `json:"name"`
+	Icon                     string   `json:"icon,omitempty"`
+	Splash                   string   `json:"splash,omitempty"`
+	DiscoverySplash          string   `json:"discovery_splash,omitempty"`
+	Emojis                   []Emoji  `json:"emojis"`
+	Features                 []string `json:"features"`
+	ApproximateMemberCount   int      `json:"approximate_member_count"`
+	ApproximatePresenceCount int      `json:"approximate_presence_count"`
+	Description              string   `json:"description,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#guild-widget-settings-object
+type GuildWidgetSettings struct {
+	Enabled   bool   `json:"enabled"`
+	ChannelID string `json:"channel_id,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#guild-widget-object
+type GuildWidget struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	InstantInvite string `json:"instant_invite,omitempty"`
+	Channels      []struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Position int    `json:"position"`
+	} `json:"channels"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Status   string `json:"status"`
+	} `json:"members"`
+	PresenceCount int `json:"presence_count"`
+}
+
+// Widget image styles
+const (
+	WidgetStyleShield  = "shield"
+	WidgetStyleBanner1 = "banner1"
+	WidgetStyleBanner2 = "banner2"
+	WidgetStyleBanner3 = "banner3"
+	WidgetStyleBanner4 = "banner4"
+)
+
+// https://discord.com/developers/docs/resources/guild#get-guild-vanity-url
+type VanityURL struct {
+	Code string `json:"code,omitempty"`
+	Uses int    `json:"uses"`
+}
+
+// https://discord.com/developers/docs/resources/guild#welcome-screen-object
+type WelcomeScreen struct {
+	Description     string                 `json:"description,omitempty"`
+	WelcomeChannels []WelcomeScreenChannel `json:"welcome_channels"`
+}
+
+type WelcomeScreenChannel struct {
+	ChannelID   string `json:"channel_id"`
+	Description string `json:"description"`
+	EmojiID     string `json:"emoji_id,omitempty"`
+	EmojiName   string `json:"emoji_name,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-welcome-screen-json-params
+type WelcomeScreenEdit struct {
+	Enabled         *bool                   `json:"enabled,omitempty"`
+	WelcomeChannels *[]WelcomeScreenChannel `json:"welcome_channels,omitempty"`
+	Description     *string                 `json:"description,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#guild-onboarding-object
+type Onboarding struct {
+	GuildID           string             `json:"guild_id,omitempty"`
+	Prompts           []OnboardingPrompt `json:"prompts"`
+	DefaultChannelIDs []string           `json:"default_channel_ids"`
+	Enabled           bool               `json:"enabled"`
+	Mode              int                `json:"mode"`
+}
+
+type OnboardingPrompt struct {
+	ID           string             `json:"id"`
+	Type         int                `json:"type"`
+	Options      []OnboardingOption `json:"options"`
+	Title        string             `json:"title"`
+	SingleSelect bool               `json:"single_select"`
+	Required     bool               `json:"required"`
+	InOnboarding bool               `json:"in_onboarding"`
+}
+
+type OnboardingOption struct {
+	ID          string   `json:"id,omitempty"`
+	ChannelIDs  []string `json:"channel_ids"`
+	RoleIDs     []string `json:"role_ids"`
+	Emoji       *Emoji   `json:"emoji,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+}
+
+// Returns a guild, with ApproximateMemberCount and ApproximatePresenceCount if withCounts is set
+func (s *Session) GetGuild(ctx context.Context, guildID string, withCounts bool, opts ...RequestOption) (*Guild, error) {
+	url := fmt.Sprintf("%s/guilds/%s?with_counts=%t", apiBase, guildID, withCounts)
+	var guild Guild
+	if err := s.requestJSON(ctx, "GET", url, nil, &guild, opts...); err != nil {
+		return nil, err
+	}
+	return &guild, nil
+}
+
+func (s *Session) ModifyGuild(ctx context.Context, guildID string, data GuildEdit, opts ...RequestOption) (*Guild, error) {
+	url := fmt.Sprintf("%s/guilds/%s", apiBase, guildID)
+	var guild Guild
+	if err := s.requestJSON(ctx, "PATCH", url, data, &guild, opts...); err != nil {
+		return nil, err
+	}
+	return &guild, nil
+}
+
+// Returns the public preview of a guild, works for discoverable guilds the bot isn't in
+func (s *Session) GetGuildPreview(ctx context.Context, guildID string, opts ...RequestOption) (*GuildPreview, error) {
+	url := fmt.Sprintf("%s/guilds/%s/preview", apiBase, guildID)
+	var preview GuildPreview
+	if err := s.requestJSON(ctx, "GET", url, nil, &preview, opts...); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// Returns the channels of a guild, not including threads
+func (s *Session) GetGuildChannels(ctx context.Context, guildID string, opts ...RequestOption) ([]Channel, error) {
+	url := fmt.Sprintf("%s/guilds/%s/channels", apiBase, guildID)
+	var channels []Channel
+	if err := s.requestJSON(ctx, "GET", url, nil, &channels, opts...); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// Returns the effective permissions of a user in a channel. Uses the State attached
+// with NewState if everything needed is cached, otherwise fetches the guild, channel and member.
+func (s *Session) ChannelPermissions(ctx context.Context, channelID string, userID string, opts ...RequestOption) (Permissions, error) {
+	if s.state != nil {
+		if perms, cached := s.state.channelPermissions(channelID, userID); cached {
+			return perms, nil
+		}
+	}
+
+	channel, err := s.GetChannel(ctx, channelID, opts...)
+	if err != nil {
+		return 0, err
+	}
+	guild, err := s.GetGuild(ctx, channel.GuildID, false, opts...)
+	if err != nil {
+		return 0, err
+	}
+	member, err := s.GetMember(ctx, channel.GuildID, userID, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	overwrites := channel.PermissionOverwrites
+	if channel.IsThread() {
+		parent, err := s.GetChannel(ctx, channel.ParentID, opts...)
+		if err != nil {
+			return 0, err
+		}
+		overwrites = parent.PermissionOverwrites
+	}
+	perms := ComputePermissions(guild.ID, guild.OwnerID, userID, member.Roles, guild.Roles, overwrites)
+	if member.TimedOut() {
+		perms = ApplyTimeout(perms)
+	}
+	return perms, nil
+}
+
+func pruneParams(days int, includeRoles []string) url.Values {
+	params := url.Values{}
+	params.Set("days", strconv.Itoa(days))
+	if len(includeRoles) > 0 {
+		params.Set("include_roles", strings.Join(includeRoles, ","))
+	}
+	return params
+}
+
+// Returns how many members would be removed by BeginPrune.
+// By default members with roles are not pruned, includeRoles adds them back.
+func (s *Session) GetPruneCount(ctx context.Context, guildID string, days int, includeRoles []string, opts ...RequestOption) (int, error) {
+	url := fmt.Sprintf("%s/guilds/%s/prune?%s", apiBase, guildID, pruneParams(days, includeRoles).Encode())
+	var resp struct {
+		Pruned int `json:"pruned"`
+	}
+	if err := s.requestJSON(ctx, "GET", url, nil, &resp, opts...); err != nil {
+		return 0, err
+	}
+	return resp.Pruned, nil
+}
+
+// Kicks members that have been inactive for the given number of days (1-30).
+// Returns the number of pruned members, or -1 if computeCount is false.
+func (s *Session) BeginPrune(ctx context.Context, guildID string, days int, includeRoles []string, computeCount bool, opts ...RequestOption) (int, error) {
+	url := fmt.Sprintf("%s/guilds/%s/prune", apiBase, guildID)
+	data := struct {
+		Days         int      `json:"days"`
+		ComputeCount bool     `json:"compute_prune_count"`
+		IncludeRoles []string `json:"include_roles,omitempty"`
+	}{days, computeCount, includeRoles}
+	var resp struct {
+		Pruned *int `json:"pruned"`
+	}
+	if err := s.requestJSON(ctx, "POST", url, data, &resp, opts...); err != nil {
+		return 0, err
+	}
+	if resp.Pruned == nil {
+		return -1, nil
+	}
+	return *resp.Pruned, nil
+}
+
+func (s *Session) GetWidgetSettings(ctx context.Context, guildID string, opts ...RequestOption) (*GuildWidgetSettings, error) {
+	url := fmt.Sprintf("%s/guilds/%s/widget", apiBase, guildID)
+	var settings GuildWidgetSettings
+	if err := s.requestJSON(ctx, "GET", url, nil, &settings, opts...); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (s *Session) ModifyWidgetSettings(ctx context.Context, guildID string, data GuildWidgetSettings, opts ...RequestOption) (*GuildWidgetSettings, error) {
+	url := fmt.Sprintf("%s/guilds/%s/widget", apiBase, guildID)
+	var settings GuildWidgetSettings
+	if err := s.requestJSON(ctx, "PATCH", url, data, &settings, opts...); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// Returns the public widget of a guild, the widget must be enabled
+func (s *Session) GetWidget(ctx context.Context, guildID string, opts ...RequestOption) (*GuildWidget, error) {
+	url := fmt.Sprintf("%s/guilds/%s/widget.json", apiBase, guildID)
+	var widget GuildWidget
+	if err := s.requestJSON(ctx, "GET", url, nil, &widget, opts...); err != nil {
+		return nil, err
+	}
+	return &widget, nil
+}
+
+// Returns the URL of the widget PNG image, style is one of the WidgetStyle* constants
+func WidgetImageURL(guildID string, style string) string {
+	if style == "" {
+		style = WidgetStyleShield
+	}
+	return fmt.Sprintf("%s/guilds/%s/widget.png?style=%s", apiBase, guildID, url.QueryEscape(style))
+}
+
+// Returns the vanity invite of a guild, Code is empty if it has none
+func (s *Session) GetVanityURL(ctx context.Context, guildID string, opts ...RequestOption) (*VanityURL, error) {
+	url := fmt.Sprintf("%s/guilds/%s/vanity-url", apiBase, guildID)
+	var vanity VanityURL
+	if err := s.requestJSON(ctx, "GET", url, nil, &vanity, opts...); err != nil {
+		return nil, err
+	}
+	return &vanity, nil
+}
+
+func (s *Session) GetWelcomeScreen(ctx context.Context, guildID string, opts ...RequestOption) (*WelcomeScreen, error) {
+	url := fmt.Sprintf("%s/guilds/%s/welcome-screen", apiBase, guildID)
+	var screen WelcomeScreen
+	if err := s.requestJSON(ctx, "GET", url, nil, &screen, opts...); err != nil {
+		return nil, err
+	}
+	return &screen, nil
+}
+
+func (s *Session) ModifyWelcomeScreen(ctx context.Context, guildID string, data WelcomeScreenEdit, opts ...RequestOption) (*WelcomeScreen, error) {
+	url := fmt.Sprintf("%s/guilds/%s/welcome-screen", apiBase, guildID)
+	var screen WelcomeScreen
+	if err := s.requestJSON(ctx, "PATCH", url, data, &screen, opts...); err != nil {
+		return nil, err
+	}
+	return &screen, nil
+}
+
+func (s *Session) GetOnboarding(ctx context.Context, guildID string, opts ...RequestOption) (*Onboarding, error) {
+	url := fmt.Sprintf("%s/guilds/%s/onboarding", apiBase, guildID)
+	var onboarding Onboarding
+	if err := s.requestJSON(ctx, "GET", url, nil, &onboarding, opts...); err != nil {
+		return nil, err
+	}
+	return &onboarding, nil
+}
+
+// Replaces the onboarding configuration of a guild
+func (s *Session) ModifyOnboarding(ctx context.Context, guildID string, data Onboarding, opts ...RequestOption) (*Onboarding, error) {
+	url := fmt.Sprintf("%s/guilds/%s/onboarding", apiBase, guildID)
+	var onboarding Onboarding
+	if err := s.requestJSON(ctx, "PUT", url, data, &onboarding, opts...); err != nil {
+		return nil, err
+	}
+	return &onboarding, nil
+}
@@ -0,0 +1,291 @@
+// Handles interactions
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-interaction-type
+const (
+	InteractionTypePing               = 1
+	InteractionTypeApplicationCommand = 2
+	InteractionTypeMessageComponent   = 3
+	InteractionTypeAutocomplete       = 4
+	InteractionTypeModalSubmit        = 5
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object
+type Interaction struct {
+	ID             string          `json:"id"`
+	ApplicationID  string          `json:"application_id"`
+	Type           int             `json:"type"`
+	Data           json.RawMessage `json:"data,omitempty"` // decode with CommandData
+	GuildID        string          `json:"guild_id,omitempty"`
+	Channel        *Channel        `json:"channel,omitempty"`
+	ChannelID      string          `json:"channel_id,omitempty"`
+	Member         *Member         `json:"member,omitempty"` // set in guilds
+	User           *User           `json:"user,omitempty"`   // set in DMs
+	Token          string          `json:"token"`
+	Version        int             `json:"version"`
+	Message        *Message        `json:"message,omitempty"` // the message a component was on
+	AppPermissions Permissions     `json:"app_permissions,omitempty"`
+	Locale         string          `json:"locale,omitempty"`
+	GuildLocale    string          `json:"guild_locale,omitempty"`
+	Context        int             `json:"context,omitempty"`
+
+	// Set for interactions received over HTTP, sends the response in the HTTP reply.
+	// Returns false if the response has to be sent through the REST API instead.
+	respond func(response InteractionResponse) bool
+}
+
+// Returns the user that triggered the interaction, in a guild or a DM
+func (i *Interaction) Author() *User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-application-command-data-structure
+type ApplicationCommandData struct {
+	ID       string               `json:"id"`
+	Name     string               `json:"name"`
+	Type     int                  `json:"type"`
+	Resolved *ResolvedData        `json:"resolved,omitempty"`
+	Options  []*InteractionOption `json:"options,omitempty"`
+	GuildID  string               `json:"guild_id,omitempty"`
+	TargetID string               `json:"target_id,omitempty"` // user or message of context menu commands
+}
+
+// Returns the data of an application command or autocomplete interaction
+func (i *Interaction) CommandData() (*ApplicationCommandData, error) {
+	if i.Type != InteractionTypeApplicationCommand && i.Type != InteractionTypeAutocomplete {
+		return nil, fmt.Errorf("interaction type %d is not a command", i.Type)
+	}
+	var data ApplicationCommandData
+	if err := json.Unmarshal(i.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-resolved-data-structure
+// Members are partial and don't include User, look it up in Users instead.
+type ResolvedData struct {
+	Users       map[string]User       `json:"users,omitempty"`
+	Members     map[string]Member     `json:"members,omitempty"`
+	Roles       map[string]Role       `json:"roles,omitempty"`
+	Channels    map[string]Channel    `json:"channels,omitempty"`
+	Messages    map[string]Message    `json:"messages,omitempty"`
+	Attachments map[string]Attachment `json:"attachments,omitempty"`
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-application-command-interaction-data-option-structure
+type InteractionOption struct {
+	Name    string               `json:"name"`
+	Type    int                  `json:"type"`
+	Value   interface{}          `json:"value,omitempty"` // string, float64 or bool
+	Options []*InteractionOption `json:"options,omitempty"`
+	Focused bool                 `json:"focused,omitempty"` // the option being autocompleted
+}
+
+func (o *InteractionOption) StringValue() string {
+	switch v := o.Value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+func (o *InteractionOption) IntValue() int64 {
+	switch v := o.Value.(type) {
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+func (o *InteractionOption) FloatValue() float64 {
+	switch v := o.Value.(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+func (o *InteractionOption) BoolValue() bool {
+	v, _ := o.Value.(bool)
+	return v
+}
+
+// https://discord.com/developers/docs/events/gateway-events#interaction-create
+type InteractionCreate struct {
+	Interaction
+}
+
+func (*InteractionCreate) EventType() string { return TypeInteractionCreate }
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object-interaction-callback-type
+const (
+	InteractionResponsePong                             = 1
+	InteractionResponseChannelMessageWithSource         = 4
+	InteractionResponseDeferredChannelMessageWithSource = 5 // Shows a loading state, edit the original response later
+	InteractionResponseDeferredUpdateMessage            = 6 // For components, edit the message later
+	InteractionResponseUpdateMessage                    = 7 // For components, edits the message the component was on
+	InteractionResponseAutocompleteResult               = 8
+	InteractionResponseModal                            = 9
+	InteractionResponseLaunchActivity                   = 12
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object
+type InteractionResponse struct {
+	Type int                      `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+// Discord requires the choices of autocomplete results even if there are none
+func (r InteractionResponse) MarshalJSON() ([]byte, error) {
+	type response InteractionResponse
+	if r.Type != InteractionResponseAutocompleteResult {
+		return json.Marshal(response(r))
+	}
+	var choices []*CommandChoice
+	if r.Data != nil {
+		choices = r.Data.Choices
+	}
+	if choices == nil {
+		choices = []*CommandChoice{}
+	}
+	type result struct {
+		Choices []*CommandChoice `json:"choices"`
+	}
+	return json.Marshal(struct {
+		Type int    `json:"type"`
+		Data result `json:"data"`
+	}{r.Type, result{choices}})
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object-interaction-callback-data-structure
+type InteractionResponseData struct {
+	TTS             bool             `json:"tts,omitempty"`
+	Content         string           `json:"content,omitempty"`
+	Embeds          []Embed          `json:"embeds,omitempty"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Flags           int              `json:"flags,omitempty"` // e.g. MessageFlagEphemeral
+	Components      []Component      `json:"components,omitempty"`
+	Poll            *PollCreate      `json:"poll,omitempty"`
+	Choices         []*CommandChoice `json:"choices,omitempty"`   // for autocomplete results
+	CustomID        string           `json:"custom_id,omitempty"` // for modals
+	Title           string           `json:"title,omitempty"`     // for modals
+	Files           []File           `json:"-"`
+}
+
+// Interaction endpoints are authorized by the interaction token, not the bot token
+func interactionOptions(opts []RequestOption) []RequestOption {
+	return append(opts[:len(opts):len(opts)], withoutAuth())
+}
+
+// Returns the webhook URL for the responses of an interaction
+func (s *Session) interactionWebhookURL(i *Interaction) string {
+	applicationID := i.ApplicationID
+	if applicationID == "" {
+		applicationID = s.ApplicationID
+	}
+	return fmt.Sprintf("%s/webhooks/%s/%s", apiBase, applicationID, i.Token)
+}
+
+// Sends the initial response to an interaction. It must be sent within 3 seconds
+// of receiving the interaction, defer if handling it takes longer.
+func (s *Session) Respond(ctx context.Context, i *Interaction, response InteractionResponse, opts ...RequestOption) error {
+	if i.respond != nil && (response.Data == nil || len(response.Data.Files) == 0) && i.respond(response) {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/interactions/%s/%s/callback", apiBase, i.ID, i.Token)
+	opts = interactionOptions(opts)
+	if response.Data == nil || len(response.Data.Files) == 0 {
+		return s.requestJSON(ctx, "POST", url, response, nil, opts...)
+	}
+
+	// The attachments belong in data, so only data goes through attachFiles
+	data, err := attachFiles(response.Data, response.Data.Files)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(struct {
+		Type int             `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}{response.Type, data})
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %v", err)
+	}
+	_, err = s.request(ctx, "POST", url, multipartBody(payload, response.Data.Files), opts...)
+	return err
+}
+
+// Acknowledges an interaction and shows "thinking..." until the original response is edited
+func (s *Session) DeferReply(ctx context.Context, i *Interaction, ephemeral bool, opts ...RequestOption) error {
+	response := InteractionResponse{Type: InteractionResponseDeferredChannelMessageWithSource}
+	if ephemeral {
+		response.Data = &InteractionResponseData{Flags: MessageFlagEphemeral}
+	}
+	return s.Respond(ctx, i, response, opts...)
+}
+
+// Acknowledges a component interaction without changing the message yet
+func (s *Session) DeferUpdate(ctx context.Context, i *Interaction, opts ...RequestOption) error {
+	return s.Respond(ctx, i, InteractionResponse{Type: InteractionResponseDeferredUpdateMessage}, opts...)
+}
+
+func (s *Session) GetOriginalResponse(ctx context.Context, i *Interaction, opts ...RequestOption) (*Message, error) {
+	var msg Message
+	if err := s.requestJSON(ctx, "GET", s.interactionWebhookURL(i)+"/messages/@original", nil, &msg, interactionOptions(opts)...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// Edits the initial response, also used to send the response after DeferReply
+func (s *Session) EditOriginalResponse(ctx context.Context, i *Interaction, data MessageEdit, opts ...RequestOption) (*Message, error) {
+	return s.EditFollowup(ctx, i, "@original", data, opts...)
+}
+
+func (s *Session) DeleteOriginalResponse(ctx context.Context, i *Interaction, opts ...RequestOption) error {
+	return s.DeleteFollowup(ctx, i, "@original", opts...)
+}
+
+// Sends another message for an interaction, can be used for 15 minutes after it was received
+func (s *Session) CreateFollowup(ctx context.Context, i *Interaction, data WebhookParams, opts ...RequestOption) (*Message, error) {
+	var msg Message
+	if err := s.requestWithFiles(ctx, "POST", s.interactionWebhookURL(i), data, data.Files, &msg, interactionOptions(opts)...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (s *Session) EditFollowup(ctx context.Context, i *Interaction, messageID string, data MessageEdit, opts ...RequestOption) (*Message, error) {
+	url := s.interactionWebhookURL(i) + "/messages/" + messageID
+	var msg Message
+	if err := s.requestWithFiles(ctx, "PATCH", url, data, data.Files, &msg, interactionOptions(opts)...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (s *Session) DeleteFollowup(ctx context.Context, i *Interaction, messageID string, opts ...RequestOption) error {
+	url := s.interactionWebhookURL(i) + "/messages/" + messageID
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, interactionOptions(opts)...)
+}
@@ -0,0 +1,149 @@
+// Handles interactions received over HTTP instead of the gateway
+package discordgowrap
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Interaction payloads are small, anything bigger isn't from Discord
+const maxInteractionBodySize = 1 << 20
+
+type interactionServer struct {
+	router    *Router
+	publicKey ed25519.PublicKey
+}
+
+// Returns an http.Handler for the interactions endpoint URL of the application.
+// publicKey is the hex encoded public key from the developer portal, requests
+// that aren't signed with it are rejected. Interactions are handled by the router
+// like INTERACTION_CREATE events, and the first response is sent as the HTTP reply.
+//
+//	handler, err := r.HTTPHandler(os.Getenv("DISCORD_PUBLIC_KEY"))
+//	...
+//	http.Handle("/interactions", handler)
+func (r *Router) HTTPHandler(publicKey string) (http.Handler, error) {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return &interactionServer{router: r, publicKey: key}, nil
+}
+
+// Checks the signature Discord sends with every request
+func verifyInteraction(publicKey ed25519.PublicKey, signature string, timestamp string, body []byte) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(publicKey, append([]byte(timestamp), body...), sig)
+}
+
+func (h *interactionServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxInteractionBodySize))
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+	// Discord sends invalid signatures on purpose to check that they are rejected
+	if !verifyInteraction(h.publicKey, req.Header.Get("X-Signature-Ed25519"), req.Header.Get("X-Signature-Timestamp"), body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var i Interaction
+	if err := json.Unmarshal(body, &i); err != nil {
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+	if i.Type == InteractionTypePing {
+		writeInteractionResponse(w, InteractionResponse{Type: InteractionResponsePong})
+		return
+	}
+
+	// The first response without files is handed over to be written as the reply,
+	// later responses go through the REST API. respond only returns once the reply
+	// has been sent, so requests made after it can't overtake the acknowledgement.
+	responses := make(chan handedResponse, 1)
+	var once sync.Once
+	i.respond = func(response InteractionResponse) bool {
+		written := make(chan bool, 1)
+		handed := false
+		once.Do(func() {
+			responses <- handedResponse{response, written}
+			handed = true
+		})
+		return handed && <-written
+	}
+
+	// The handler can keep sending follow-ups after the reply, so it must not be
+	// canceled when the request finishes
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := h.router.HandleInteraction(context.WithoutCancel(req.Context()), &i); err != nil {
+			log.Printf("[Router] Failed to handle interaction %s: %v\n", i.ID, err)
+		}
+	}()
+
+	select {
+	case handed := <-responses:
+		handed.written <- writeInteractionResponse(w, handed.response)
+	case <-done:
+		select {
+		case handed := <-responses:
+			handed.written <- writeInteractionResponse(w, handed.response)
+		default:
+			// Either nothing responded or the response was sent through the REST API
+			once.Do(func() {})
+			w.WriteHeader(http.StatusAccepted)
+		}
+	case <-req.Context().Done():
+		// A response handed over meanwhile falls back to the REST API
+		once.Do(func() {})
+		select {
+		case handed := <-responses:
+			handed.written <- false
+		default:
+		}
+	}
+}
+
+type handedResponse struct {
+	response InteractionResponse
+	written  chan bool
+}
+
+// Writes and flushes the reply, returns whether it was sent
+func writeInteractionResponse(w http.ResponseWriter, response InteractionResponse) bool {
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("[Router] Failed to marshal interaction response: %v\n", err)
+		http.Error(w, "error marshaling response", http.StatusInternalServerError)
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	// With a known length the reply is complete once flushed, instead of when ServeHTTP returns
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if _, err := w.Write(data); err != nil {
+		return false
+	}
+	err = http.NewResponseController(w).Flush()
+	return err == nil || errors.Is(err, http.ErrNotSupported)
+}
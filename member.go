@@ -0,0 +1,303 @@
+// Handles guild members, kicks and bans
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// https://discord.com/developers/docs/resources/guild#guild-member-object
+type Member struct {
+	User                       *User       `json:"user,omitempty"`
+	Nick                       string      `json:"nick,omitempty"`
+	Avatar                     string      `json:"avatar,omitempty"`
+	Roles                      []string    `json:"roles"`
+	JoinedAt                   time.Time   `json:"joined_at"`
+	PremiumSince               *time.Time  `json:"premium_since,omitempty"`
+	Deaf                       bool        `json:"deaf"`
+	Mute                       bool        `json:"mute"`
+	Flags                      int         `json:"flags"`
+	Pending                    bool        `json:"pending,omitempty"`
+	Permissions                Permissions `json:"permissions,omitempty"` // only sent in interactions
+	CommunicationDisabledUntil *time.Time  `json:"communication_disabled_until,omitempty"`
+	GuildID                    string      `json:"guild_id,omitempty"` // only sent in gateway events
+}
+
+// Returns the name shown for the member in the guild
+func (m *Member) DisplayName() string {
+	if m.Nick != "" {
+		return m.Nick
+	}
+	if m.User == nil {
+		return ""
+	}
+	if m.User.GlobalName != "" {
+		return m.User.GlobalName
+	}
+	return m.User.Name
+}
+
+// Returns whether the member is currently timed out
+func (m *Member) TimedOut() bool {
+	return m.CommunicationDisabledUntil != nil && m.CommunicationDisabledUntil.After(time.Now())
+}
+
+// https://discord.com/developers/docs/events/gateway-events#guild-member-add
+// Requires IntentGuildMembers, like the other member events.
+type GuildMemberAdd struct {
+	Member
+}
+
+func (*GuildMemberAdd) EventType() string { return TypeGuildMemberAdd }
+
+// https://discord.com/developers/docs/events/gateway-events#guild-member-update
+type GuildMemberUpdate struct {
+	Member
+}
+
+func (*GuildMemberUpdate) EventType() string { return TypeGuildMemberUpdate }
+
+// https://discord.com/developers/docs/events/gateway-events#guild-member-remove
+type GuildMemberRemove struct {
+	GuildID string `json:"guild_id"`
+	User    User   `json:"user"`
+}
+
+func (*GuildMemberRemove) EventType() string { return TypeGuildMemberRemove }
+
+// https://discord.com/developers/docs/events/gateway-events#guild-members-chunk
+// Sent in response to a request for guild members.
+type GuildMembersChunk struct {
+	GuildID    string   `json:"guild_id"`
+	Members    []Member `json:"members"`
+	ChunkIndex int      `json:"chunk_index"`
+	ChunkCount int      `json:"chunk_count"`
+	NotFound   []string `json:"not_found,omitempty"`
+	Nonce      string   `json:"nonce,omitempty"`
+}
+
+func (*GuildMembersChunk) EventType() string { return TypeGuildMembersChunk }
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-member-json-params
+// Nil fields are left unchanged.
+type MemberEdit struct {
+	Nick  *string   `json:"nick,omitempty"`
+	Roles *[]string `json:"roles,omitempty"`
+	Mute  *bool     `json:"mute,omitempty"`
+	Deaf  *bool     `json:"deaf,omitempty"`
+	// Moves the member to another voice channel, use DisconnectMember to disconnect them
+	ChannelID *string `json:"channel_id,omitempty"`
+	// Times the member out until then, at most 28 days ahead. Use RemoveTimeout to clear it.
+	CommunicationDisabledUntil *time.Time `json:"communication_disabled_until,omitempty"`
+	Flags                      *int       `json:"flags,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#ban-object
+type Ban struct {
+	Reason string `json:"reason"`
+	User   User   `json:"user"`
+}
+
+// https://discord.com/developers/docs/resources/guild#bulk-guild-ban-bulk-ban-response
+type BulkBanResponse struct {
+	BannedUsers []string `json:"banned_users"`
+	FailedUsers []string `json:"failed_users"`
+}
+
+func (s *Session) GetMember(ctx context.Context, guildID string, userID string, opts ...RequestOption) (*Member, error) {
+	url := fmt.Sprintf("%s/guilds/%s/members/%s", apiBase, guildID, userID)
+	var member Member
+	if err := s.requestJSON(ctx, "GET", url, nil, &member, opts...); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Returns an iterator over every member of a guild, fetched 1000 at a time.
+// Requires the GUILD_MEMBERS privileged intent.
+func (s *Session) GuildMembers(ctx context.Context, guildID string, opts ...RequestOption) iter.Seq2[Member, error] {
+	return func(yield func(Member, error) bool) {
+		after := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(Member{}, err)
+				return
+			}
+
+			params := url.Values{}
+			params.Set("limit", "1000")
+			if after != "" {
+				params.Set("after", after)
+			}
+			endpoint := fmt.Sprintf("%s/guilds/%s/members?%s", apiBase, guildID, params.Encode())
+			var page []Member
+			if err := s.requestJSON(ctx, "GET", endpoint, nil, &page, opts...); err != nil {
+				yield(Member{}, err)
+				return
+			}
+			for _, member := range page {
+				if !yield(member, nil) {
+					return
+				}
+			}
+			if len(page) < 1000 || page[len(page)-1].User == nil {
+				return
+			}
+			after = page[len(page)-1].User.ID
+		}
+	}
+}
+
+// Returns up to limit (1-1000) members whose username or nickname starts with query.
+// A limit of 0 or less uses Discord's default of 1.
+func (s *Session) SearchMembers(ctx context.Context, guildID string, query string, limit int, opts ...RequestOption) ([]Member, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(min(limit, 1000)))
+	}
+	endpoint := fmt.Sprintf("%s/guilds/%s/members/search?%s", apiBase, guildID, params.Encode())
+	var members []Member
+	if err := s.requestJSON(ctx, "GET", endpoint, nil, &members, opts...); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (s *Session) ModifyMember(ctx context.Context, guildID string, userID string, data MemberEdit, opts ...RequestOption) (*Member, error) {
+	url := fmt.Sprintf("%s/guilds/%s/members/%s", apiBase, guildID, userID)
+	var member Member
+	if err := s.requestJSON(ctx, "PATCH", url, data, &member, opts...); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Changes the nickname of the bot itself, an empty nick resets it
+func (s *Session) ModifyCurrentMember(ctx context.Context, guildID string, nick string, opts ...RequestOption) (*Member, error) {
+	url := fmt.Sprintf("%s/guilds/%s/members/@me", apiBase, guildID)
+	data := map[string]interface{}{"nick": nil}
+	if nick != "" {
+		data["nick"] = nick
+	}
+	var member Member
+	if err := s.requestJSON(ctx, "PATCH", url, data, &member, opts...); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Times a member out for the given duration, at most 28 days
+func (s *Session) TimeoutMember(ctx context.Context, guildID string, userID string, duration time.Duration, opts ...RequestOption) error {
+	until := time.Now().Add(duration)
+	_, err := s.ModifyMember(ctx, guildID, userID, MemberEdit{CommunicationDisabledUntil: &until}, opts...)
+	return err
+}
+
+func (s *Session) RemoveTimeout(ctx context.Context, guildID string, userID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/members/%s", apiBase, guildID, userID)
+	data := map[string]interface{}{"communication_disabled_until": nil}
+	return s.requestJSON(ctx, "PATCH", url, data, nil, opts...)
+}
+
+// Disconnects a member from their voice channel
+func (s *Session) DisconnectMember(ctx context.Context, guildID string, userID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/members/%s", apiBase, guildID, userID)
+	data := map[string]interface{}{"channel_id": nil}
+	return s.requestJSON(ctx, "PATCH", url, data, nil, opts...)
+}
+
+func (s *Session) AddMemberRole(ctx context.Context, guildID string, userID string, roleID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/members/%s/roles/%s", apiBase, guildID, userID, roleID)
+	return s.requestJSON(ctx, "PUT", url, nil, nil, opts...)
+}
+
+func (s *Session) RemoveMemberRole(ctx context.Context, guildID string, userID string, roleID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/members/%s/roles/%s", apiBase, guildID, userID, roleID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+func (s *Session) KickMember(ctx context.Context, guildID string, userID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/members/%s", apiBase, guildID, userID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+// Bans a user, deleting their messages from the last deleteMessageSeconds (at most 7 days)
+func (s *Session) BanMember(ctx context.Context, guildID string, userID string, deleteMessageSeconds int, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/bans/%s", apiBase, guildID, userID)
+	data := struct {
+		DeleteMessageSeconds int `json:"delete_message_seconds,omitempty"`
+	}{deleteMessageSeconds}
+	return s.requestJSON(ctx, "PUT", url, data, nil, opts...)
+}
+
+func (s *Session) UnbanMember(ctx context.Context, guildID string, userID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/bans/%s", apiBase, guildID, userID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+// Bans users in chunks of 200, the responses of every chunk are combined
+func (s *Session) BulkBan(ctx context.Context, guildID string, userIDs []string, deleteMessageSeconds int, opts ...RequestOption) (*BulkBanResponse, error) {
+	url := fmt.Sprintf("%s/guilds/%s/bulk-ban", apiBase, guildID)
+	var result BulkBanResponse
+	for start := 0; start < len(userIDs); start += 200 {
+		data := struct {
+			UserIDs              []string `json:"user_ids"`
+			DeleteMessageSeconds int      `json:"delete_message_seconds,omitempty"`
+		}{userIDs[start:min(start+200, len(userIDs))], deleteMessageSeconds}
+		var resp BulkBanResponse
+		if err := s.requestJSON(ctx, "POST", url, data, &resp, opts...); err != nil {
+			return &result, err
+		}
+		result.BannedUsers = append(result.BannedUsers, resp.BannedUsers...)
+		result.FailedUsers = append(result.FailedUsers, resp.FailedUsers...)
+	}
+	return &result, nil
+}
+
+func (s *Session) GetBan(ctx context.Context, guildID string, userID string, opts ...RequestOption) (*Ban, error) {
+	url := fmt.Sprintf("%s/guilds/%s/bans/%s", apiBase, guildID, userID)
+	var ban Ban
+	if err := s.requestJSON(ctx, "GET", url, nil, &ban, opts...); err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
+
+// Returns an iterator over the bans of a guild, fetched 1000 at a time
+func (s *Session) GuildBans(ctx context.Context, guildID string, opts ...RequestOption) iter.Seq2[Ban, error] {
+	return func(yield func(Ban, error) bool) {
+		after := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(Ban{}, err)
+				return
+			}
+
+			params := url.Values{}
+			params.Set("limit", "1000")
+			if after != "" {
+				params.Set("after", after)
+			}
+			endpoint := fmt.Sprintf("%s/guilds/%s/bans?%s", apiBase, guildID, params.Encode())
+			var page []Ban
+			if err := s.requestJSON(ctx, "GET", endpoint, nil, &page, opts...); err != nil {
+				yield(Ban{}, err)
+				return
+			}
+			for _, ban := range page {
+				if !yield(ban, nil) {
+					return
+				}
+			}
+			if len(page) < 1000 {
+				return
+			}
+			after = page[len(page)-1].User.ID
+		}
+	}
+}
@@ -0,0 +1,402 @@
+// Handles messages
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// https://discord.com/developers/docs/resources/message#message-object-message-flags
+const (
+	MessageFlagCrossposted           = 1 << 0
+	MessageFlagIsCrosspost           = 1 << 1
+	MessageFlagSuppressEmbeds        = 1 << 2
+	MessageFlagUrgent                = 1 << 4
+	MessageFlagHasThread             = 1 << 5
+	MessageFlagEphemeral             = 1 << 6
+	MessageFlagLoading               = 1 << 7
+	MessageFlagSuppressNotifications = 1 << 12 // Sends the message silently
+	MessageFlagIsVoiceMessage        = 1 << 13
+	MessageFlagIsComponentsV2        = 1 << 15
+)
+
+// https://discord.com/developers/docs/resources/message#message-object
+type Message struct {
+	ID               string            `json:"id"`
+	ChannelID        string            `json:"channel_id"`
+	GuildID          string            `json:"guild_id,omitempty"`
+	Author           User              `json:"author"`
+	Content          string            `json:"content"`
+	Timestamp        time.Time         `json:"timestamp"`
+	EditedTimestamp  *time.Time        `json:"edited_timestamp"`
+	TTS              bool              `json:"tts"`
+	MentionEveryone  bool              `json:"mention_everyone"`
+	Mentions         []User            `json:"mentions"`
+	MentionRoles     []string          `json:"mention_roles"`
+	Attachments      []Attachment      `json:"attachments"`
+	Embeds           []Embed           `json:"embeds"`
+	Reactions        []Reaction        `json:"reactions,omitempty"`
+	Pinned           bool              `json:"pinned"`
+	WebhookID        string            `json:"webhook_id,omitempty"`
+	Type             int               `json:"type"`
+	Flags            int               `json:"flags"`
+	MessageReference *MessageReference `json:"message_reference,omitempty"`
+	// Only set for replies, nil if the referenced message was deleted
+	ReferencedMessage *Message `json:"referenced_message,omitempty"`
+	// The thread started from this message, if any
+	Thread *Channel `json:"thread,omitempty"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#message-update
+type MessageUpdate struct {
+	Message
+}
+
+func (*MessageUpdate) EventType() string { return TypeMessageUpdate }
+
+// https://discord.com/developers/docs/events/gateway-events#message-delete
+type MessageDelete struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	GuildID   string `json:"guild_id,omitempty"`
+}
+
+func (*MessageDelete) EventType() string { return TypeMessageDelete }
+
+// https://discord.com/developers/docs/events/gateway-events#message-delete-bulk
+type MessageDeleteBulk struct {
+	IDs       []string `json:"ids"`
+	ChannelID string   `json:"channel_id"`
+	GuildID   string   `json:"guild_id,omitempty"`
+}
+
+func (*MessageDeleteBulk) EventType() string { return TypeMessageDeleteBulk }
+
+// https://discord.com/developers/docs/resources/message#attachment-object
+type Attachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	Description string `json:"description,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size"`
+	URL         string `json:"url"`
+	ProxyURL    string `json:"proxy_url"`
+	Height      int    `json:"height,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Ephemeral   bool   `json:"ephemeral,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/message#embed-object
+type Embed struct {
+	Title       string         `json:"title,omitempty"`
+	Type        string         `json:"type,omitempty"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Timestamp   *time.Time     `json:"timestamp,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Footer      *EmbedFooter   `json:"footer,omitempty"`
+	Image       *EmbedMedia    `json:"image,omitempty"`
+	Thumbnail   *EmbedMedia    `json:"thumbnail,omitempty"`
+	Video       *EmbedMedia    `json:"video,omitempty"`
+	Provider    *EmbedProvider `json:"provider,omitempty"`
+	Author      *EmbedAuthor   `json:"author,omitempty"`
+	Fields      []EmbedField   `json:"fields,omitempty"`
+}
+
+type EmbedFooter struct {
+	Text    string `json:"text"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type EmbedMedia struct {
+	URL    string `json:"url"`
+	Height int    `json:"height,omitempty"`
+	Width  int    `json:"width,omitempty"`
+}
+
+type EmbedProvider struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type EmbedAuthor struct {
+	Name    string `json:"name"`
+	URL     string `json:"url,omitempty"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/message#message-reference-structure
+type MessageReference struct {
+	MessageID string `json:"message_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+	GuildID   string `json:"guild_id,omitempty"`
+	// Errors if the referenced message doesn't exist instead of sending a normal message
+	FailIfNotExists *bool `json:"fail_if_not_exists,omitempty"`
+}
+
+// Allowed mention types
+const (
+	AllowedMentionRoles    = "roles"
+	AllowedMentionUsers    = "users"
+	AllowedMentionEveryone = "everyone"
+)
+
+// https://discord.com/developers/docs/resources/message#allowed-mentions-object
+// The zero value allows no mentions at all.
+type AllowedMentions struct {
+	Parse       []string `json:"parse"`
+	Roles       []string `json:"roles,omitempty"`
+	Users       []string `json:"users,omitempty"`
+	RepliedUser bool     `json:"replied_user,omitempty"`
+}
+
+// Sends "parse": [] when Parse is nil, Discord treats a missing parse as the default mentions
+func (a AllowedMentions) MarshalJSON() ([]byte, error) {
+	type allowedMentions AllowedMentions
+	if a.Parse == nil {
+		a.Parse = []string{}
+	}
+	return json.Marshal(allowedMentions(a))
+}
+
+// https://discord.com/developers/docs/resources/poll#poll-create-request-object
+type PollCreate struct {
+	Question         PollMedia    `json:"question"`
+	Answers          []PollAnswer `json:"answers"`
+	Duration         int          `json:"duration,omitempty"` // hours
+	AllowMultiselect bool         `json:"allow_multiselect,omitempty"`
+}
+
+type PollMedia struct {
+	Text  string     `json:"text,omitempty"`
+	Emoji *PollEmoji `json:"emoji,omitempty"`
+}
+
+type PollEmoji struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type PollAnswer struct {
+	AnswerID  int       `json:"answer_id,omitempty"`
+	PollMedia PollMedia `json:"poll_media"`
+}
+
+// A message component such as an action row or a button
+type Component interface {
+	ComponentType() int
+}
+
+// https://discord.com/developers/docs/resources/message#create-message-jsonform-params
+type MessageSend struct {
+	Content          string            `json:"content,omitempty"`
+	TTS              bool              `json:"tts,omitempty"`
+	Embeds           []Embed           `json:"embeds,omitempty"`
+	AllowedMentions  *AllowedMentions  `json:"allowed_mentions,omitempty"`
+	MessageReference *MessageReference `json:"message_reference,omitempty"`
+	Components       []Component       `json:"components,omitempty"`
+	StickerIDs       []string          `json:"sticker_ids,omitempty"`
+	Flags            int               `json:"flags,omitempty"`
+	Poll             *PollCreate       `json:"poll,omitempty"`
+	Files            []File            `json:"-"`
+}
+
+// Sets the message up as a reply to another message
+func (m *MessageSend) Reply(channelID string, messageID string) {
+	m.MessageReference = &MessageReference{
+		MessageID: messageID,
+		ChannelID: channelID,
+	}
+}
+
+func (s *Session) SendMessage(ctx context.Context, channelID string, content string, opts ...RequestOption) error {
+	_, err := s.SendMessageComplex(ctx, channelID, MessageSend{Content: content}, opts...)
+	return err
+}
+
+// Sends a message with embeds, replies, components etc. and returns the created message
+func (s *Session) SendMessageComplex(ctx context.Context, channelID string, data MessageSend, opts ...RequestOption) (*Message, error) {
+	url := fmt.Sprintf("%s/channels/%s/messages", apiBase, channelID)
+	var msg Message
+	if err := s.requestWithFiles(ctx, "POST", url, data, data.Files, &msg, opts...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// https://discord.com/developers/docs/resources/message#edit-message-jsonform-params
+// Fields left nil are not changed, pointers to empty slices remove all embeds,
+// components or attachments.
+type MessageEdit struct {
+	Content         *string          `json:"content,omitempty"`
+	Embeds          *[]Embed         `json:"embeds,omitempty"`
+	Flags           *int             `json:"flags,omitempty"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Components      *[]Component     `json:"components,omitempty"`
+	// Attachments to keep. All attachments are kept if nil, unless Files are
+	// uploaded in which case the existing attachments must be listed here.
+	Attachments *[]Attachment `json:"attachments,omitempty"`
+	Files       []File        `json:"-"`
+}
+
+// Returns a single message by ID
+func (s *Session) GetChannelMessage(ctx context.Context, channelID string, messageID string, opts ...RequestOption) (*Message, error) {
+	url := fmt.Sprintf("%s/channels/%s/messages/%s", apiBase, channelID, messageID)
+	var msg Message
+	if err := s.requestJSON(ctx, "GET", url, nil, &msg, opts...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (s *Session) EditMessage(ctx context.Context, channelID string, messageID string, data MessageEdit, opts ...RequestOption) (*Message, error) {
+	url := fmt.Sprintf("%s/channels/%s/messages/%s", apiBase, channelID, messageID)
+	var msg Message
+	if err := s.requestWithFiles(ctx, "PATCH", url, data, data.Files, &msg, opts...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (s *Session) DeleteMessage(ctx context.Context, channelID string, messageID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/messages/%s", apiBase, channelID, messageID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+// Messages older than this can't be bulk deleted
+const bulkDeleteMaxAge = 14 * 24 * time.Hour
+
+// Deletes messages in chunks of 100. Messages older than 14 days are skipped
+// since Discord rejects them, they have to be deleted one by one with DeleteMessage.
+func (s *Session) BulkDeleteMessages(ctx context.Context, channelID string, messageIDs []string, opts ...RequestOption) error {
+	// Leave a minute of margin so messages don't age out while the request is in flight
+	cutoff := time.Now().Add(-bulkDeleteMaxAge + time.Minute)
+	ids := make([]string, 0, len(messageIDs))
+	for _, id := range messageIDs {
+		created, err := SnowflakeTime(id)
+		if err != nil {
+			return fmt.Errorf("invalid message ID %q: %v", id, err)
+		}
+		if created.Before(cutoff) {
+			log.Printf("BulkDeleteMessages: skipping message %s older than 14 days\n", id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/messages/bulk-delete", apiBase, channelID)
+	for start := 0; start < len(ids); start += 100 {
+		chunk := ids[start:min(start+100, len(ids))]
+		// Bulk delete requires at least 2 messages
+		if len(chunk) == 1 {
+			if err := s.DeleteMessage(ctx, channelID, chunk[0], opts...); err != nil {
+				return err
+			}
+			continue
+		}
+		data := struct {
+			Messages []string `json:"messages"`
+		}{chunk}
+		if err := s.requestJSON(ctx, "POST", url, data, nil, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publishes a message in an announcement channel to the channels following it
+func (s *Session) CrosspostMessage(ctx context.Context, channelID string, messageID string, opts ...RequestOption) (*Message, error) {
+	url := fmt.Sprintf("%s/channels/%s/messages/%s/crosspost", apiBase, channelID, messageID)
+	var msg Message
+	if err := s.requestJSON(ctx, "POST", url, nil, &msg, opts...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// Where to start reading channel messages from, only one of the IDs should be set.
+// With none set, reading starts at the newest message.
+type MessagesQuery struct {
+	Before string // Pages backwards from this message, newest first
+	After  string // Pages forwards from this message, oldest first
+	Around string // Returns up to 100 messages around this message, no further pages
+	Limit  int    // Total number of messages to return, 0 for all
+}
+
+// Returns an iterator over the messages in a channel, fetched 100 at a time.
+// Iteration stops after the first error, including the context being cancelled.
+//
+//	for msg, err := range s.ChannelMessages(ctx, channelID, MessagesQuery{}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (s *Session) ChannelMessages(ctx context.Context, channelID string, query MessagesQuery, opts ...RequestOption) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		before, after := query.Before, query.After
+		returned := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(Message{}, err)
+				return
+			}
+
+			limit := 100
+			if query.Limit > 0 {
+				limit = min(limit, query.Limit-returned)
+			}
+			params := url.Values{}
+			params.Set("limit", strconv.Itoa(limit))
+			switch {
+			case query.Around != "":
+				params.Set("around", query.Around)
+			case after != "":
+				params.Set("after", after)
+			case before != "":
+				params.Set("before", before)
+			}
+
+			endpoint := fmt.Sprintf("%s/channels/%s/messages?%s", apiBase, channelID, params.Encode())
+			var page []Message
+			if err := s.requestJSON(ctx, "GET", endpoint, nil, &page, opts...); err != nil {
+				yield(Message{}, err)
+				return
+			}
+			if len(page) == 0 {
+				return
+			}
+
+			// Discord returns newest first, yield oldest first when paging forwards
+			if after != "" && query.Around == "" {
+				slices.Reverse(page)
+				after = page[len(page)-1].ID
+			} else {
+				before = page[len(page)-1].ID
+			}
+			for _, msg := range page {
+				if !yield(msg, nil) {
+					return
+				}
+			}
+
+			returned += len(page)
+			if query.Around != "" || len(page) < limit || (query.Limit > 0 && returned >= query.Limit) {
+				return
+			}
+		}
+	}
+}
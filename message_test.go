@@ -0,0 +1,58 @@
+package discordgowrap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAllowedMentionsMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		mentions AllowedMentions
+		want     string
+	}{
+		{"zero value", AllowedMentions{}, `{"parse":[]}`},
+		{"parse users", AllowedMentions{Parse: []string{AllowedMentionUsers}}, `{"parse":["users"]}`},
+		{"explicit roles", AllowedMentions{Roles: []string{"123"}, RepliedUser: true}, `{"parse":[],"roles":["123"],"replied_user":true}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.mentions)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, data, tt.want)
+		}
+	}
+
+	// A pointer inside a message uses the same marshaller
+	data, err := json.Marshal(MessageSend{Content: "hi", AllowedMentions: &AllowedMentions{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"content":"hi","allowed_mentions":{"parse":[]}}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestMessageEditMarshal(t *testing.T) {
+	none := []Component{}
+	tests := []struct {
+		name string
+		edit MessageEdit
+		want string
+	}{
+		{"keep everything", MessageEdit{}, `{}`},
+		{"remove components", MessageEdit{Components: &none}, `{"components":[]}`},
+		{"remove embeds and attachments", MessageEdit{Embeds: &[]Embed{}, Attachments: &[]Attachment{}}, `{"embeds":[],"attachments":[]}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.edit)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, data, tt.want)
+		}
+	}
+}
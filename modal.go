@@ -0,0 +1,257 @@
+// Handles modals
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// https://discord.com/developers/docs/components/reference#text-input-text-input-styles
+const (
+	TextInputStyleShort     = 1 // Single line
+	TextInputStyleParagraph = 2 // Multiple lines
+)
+
+// https://discord.com/developers/docs/components/reference#text-input
+// Text inputs are only allowed in modals.
+type TextInput struct {
+	ID          int    `json:"id,omitempty"`
+	CustomID    string `json:"custom_id"`
+	Style       int    `json:"style"`
+	Label       string `json:"label"`
+	MinLength   int    `json:"min_length,omitempty"` // 0 to 4000
+	MaxLength   int    `json:"max_length,omitempty"` // 1 to 4000
+	Required    *bool  `json:"required,omitempty"`   // defaults to true
+	Value       string `json:"value,omitempty"`      // prefilled text
+	Placeholder string `json:"placeholder,omitempty"`
+}
+
+func (TextInput) ComponentType() int { return ComponentTypeTextInput }
+
+func (t TextInput) MarshalJSON() ([]byte, error) {
+	type textInput TextInput
+	return marshalComponent(textInput(t), t.ComponentType())
+}
+
+func NewTextInput(customID string, label string, style int) TextInput {
+	return TextInput{CustomID: customID, Label: label, Style: style}
+}
+
+// Sets whether the input must be filled in
+func (t TextInput) SetRequired(required bool) TextInput {
+	t.Required = &required
+	return t
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object-modal
+type Modal struct {
+	CustomID   string
+	Title      string
+	Components []Component // up to 5 action rows with one text input each
+}
+
+// Creates a modal with each text input in its own action row
+func NewModal(customID string, title string, inputs ...TextInput) Modal {
+	modal := Modal{CustomID: customID, Title: title}
+	for _, input := range inputs {
+		modal.Components = append(modal.Components, NewActionRow(input))
+	}
+	return modal
+}
+
+// Responds to a command or component interaction by showing a modal.
+// Modal submit and autocomplete interactions can't be responded to with a modal.
+func (s *Session) RespondModal(ctx context.Context, i *Interaction, modal Modal, opts ...RequestOption) error {
+	return s.Respond(ctx, i, InteractionResponse{
+		Type: InteractionResponseModal,
+		Data: &InteractionResponseData{
+			CustomID:   modal.CustomID,
+			Title:      modal.Title,
+			Components: modal.Components,
+		},
+	}, opts...)
+}
+
+func (c *InteractionContext) ShowModal(modal Modal) error {
+	return c.Session.RespondModal(c, c.Interaction, modal)
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-modal-submit-data-structure
+type ModalSubmitData struct {
+	CustomID   string                 `json:"custom_id"`
+	Components []ModalSubmitComponent `json:"components"`
+	Resolved   *ResolvedData          `json:"resolved,omitempty"`
+}
+
+// A submitted component, text inputs are nested in action rows
+type ModalSubmitComponent struct {
+	Type       int                    `json:"type"`
+	ID         int                    `json:"id,omitempty"`
+	CustomID   string                 `json:"custom_id,omitempty"`
+	Value      string                 `json:"value,omitempty"`  // text inputs
+	Values     []string               `json:"values,omitempty"` // select menus
+	Components []ModalSubmitComponent `json:"components,omitempty"`
+	Component  *ModalSubmitComponent  `json:"component,omitempty"` // labels
+}
+
+// Returns the data of a modal submit interaction
+func (i *Interaction) ModalData() (*ModalSubmitData, error) {
+	if i.Type != InteractionTypeModalSubmit {
+		return nil, fmt.Errorf("interaction type %d is not a modal submit", i.Type)
+	}
+	var data ModalSubmitData
+	if err := json.Unmarshal(i.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Returns the submitted text input values by custom ID
+func (d *ModalSubmitData) Values() map[string]string {
+	values := make(map[string]string)
+	var walk func(components []ModalSubmitComponent)
+	walk = func(components []ModalSubmitComponent) {
+		for _, c := range components {
+			if c.Type == ComponentTypeTextInput {
+				values[c.CustomID] = c.Value
+			}
+			walk(c.Components)
+			if c.Component != nil {
+				walk([]ModalSubmitComponent{*c.Component})
+			}
+		}
+	}
+	walk(d.Components)
+	return values
+}
+
+type ModalHandler func(c *ModalContext) error
+
+// Passed to modal handlers, Route is the custom ID prefix the handler was registered for
+type ModalContext struct {
+	InteractionContext
+	Data   *ModalSubmitData
+	Params []string          // the parts of the custom ID after the prefix
+	Values map[string]string // the submitted text inputs by custom ID
+}
+
+// Returns the parameter at index, or an empty string if there are fewer parameters
+func (c *ModalContext) Param(index int) string {
+	if index < 0 || index >= len(c.Params) {
+		return ""
+	}
+	return c.Params[index]
+}
+
+// Registers a handler for modals whose custom ID is prefix, or starts with prefix
+// followed by parameters as built by CustomID. The longest matching prefix is used.
+func (r *Router) HandleModal(prefix string, handler ModalHandler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.modals[prefix] = handler
+}
+
+// Registers a modal handler that receives the submitted values in the tagged fields of T.
+//
+//	type Feedback struct {
+//		Title string `modal:"title"`
+//		Body  string `modal:"body"`
+//		Stars int    `modal:"stars"`
+//	}
+//
+// Fields can be strings, integers, floats or bools, values that can't be parsed are
+// returned as an error. Panics if T is not a struct or has unsupported fields.
+func ModalSubmit[T any](r *Router, prefix string, handler func(c *ModalContext, form *T) error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("discordgowrap: modal %q: form must be a struct, got %s", prefix, t))
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup("modal"); !ok {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		default:
+			panic(fmt.Sprintf("discordgowrap: modal %q: field %s has unsupported type %s", prefix, field.Name, field.Type))
+		}
+	}
+
+	r.HandleModal(prefix, func(c *ModalContext) error {
+		var form T
+		if err := bindModal(reflect.ValueOf(&form).Elem(), c.Values); err != nil {
+			return err
+		}
+		return handler(c, &form)
+	})
+}
+
+// Fills the tagged fields of form from the submitted values
+func bindModal(form reflect.Value, values map[string]string) error {
+	t := form.Type()
+	for i := 0; i < t.NumField(); i++ {
+		customID, ok := t.Field(i).Tag.Lookup("modal")
+		if !ok {
+			continue
+		}
+		value, exists := values[customID]
+		if !exists || value == "" {
+			continue
+		}
+
+		field := form.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s must be a whole number", customID)
+			}
+			field.SetInt(n)
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s must be a number", customID)
+			}
+			field.SetFloat(f)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s must be true or false", customID)
+			}
+			field.SetBool(b)
+		}
+	}
+	return nil
+}
+
+func (r *Router) handleModal(ctx context.Context, i *Interaction) error {
+	data, err := i.ModalData()
+	if err != nil {
+		return err
+	}
+	handler, prefix, params, exists := matchCustomID(r, r.modals, data.CustomID)
+	if !exists {
+		return fmt.Errorf("no handler for modal %q", data.CustomID)
+	}
+
+	c := &ModalContext{
+		InteractionContext: InteractionContext{
+			Context:     ctx,
+			Session:     r.session,
+			Interaction: i,
+			Route:       prefix,
+		},
+		Data:   data,
+		Params: params,
+		Values: data.Values(),
+	}
+	r.handleError(&c.InteractionContext, handler(c))
+	return nil
+}
@@ -0,0 +1,146 @@
+// Handles file uploads
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+)
+
+var errBodyNotReplayable = errors.New("cannot resend upload, file reader is not seekable")
+
+// A file to upload with a message.
+// Readers implementing io.Seeker can be resent after a rate limit.
+type File struct {
+	Name        string
+	Reader      io.Reader
+	Description string
+	Spoiler     bool
+}
+
+func (f *File) filename() string {
+	if f.Spoiler {
+		return "SPOILER_" + f.Name
+	}
+	return f.Name
+}
+
+// https://discord.com/developers/docs/reference#uploading-files
+type partialAttachment struct {
+	ID          int    `json:"id"`
+	Filename    string `json:"filename"`
+	Description string `json:"description,omitempty"`
+}
+
+// Returns a multipart/form-data body with payload as payload_json and the files as files[n].
+// Files are streamed through a pipe so they are never fully held in memory.
+func multipartBody(payload []byte, files []File) requestBody {
+	offsets := make([]int64, len(files))
+	sent := false
+	var prev *io.PipeReader
+	var done chan struct{} // closed once the previous attempt's writer has returned
+	return func() (io.Reader, string, error) {
+		// Stop the previous writer before touching the readers it copies from
+		if prev != nil {
+			prev.CloseWithError(errBodyNotReplayable)
+			<-done
+		}
+
+		// Rewind the readers if the body is being resent
+		for i, f := range files {
+			seeker, ok := f.Reader.(io.Seeker)
+			switch {
+			case !sent && ok:
+				offset, err := seeker.Seek(0, io.SeekCurrent)
+				if err != nil {
+					return nil, "", err
+				}
+				offsets[i] = offset
+			case sent && ok:
+				if _, err := seeker.Seek(offsets[i], io.SeekStart); err != nil {
+					return nil, "", err
+				}
+			case sent:
+				return nil, "", errBodyNotReplayable
+			}
+		}
+		sent = true
+
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+		prev, done = pr, make(chan struct{})
+		go func(done chan struct{}) {
+			defer close(done)
+			pw.CloseWithError(writeMultipart(mw, payload, files))
+		}(done)
+		return pr, mw.FormDataContentType(), nil
+	}
+}
+
+func writeMultipart(mw *multipart.Writer, payload []byte, files []File) error {
+	if err := mw.WriteField("payload_json", string(payload)); err != nil {
+		return err
+	}
+	for i, f := range files {
+		part, err := mw.CreateFormFile(fmt.Sprintf("files[%d]", i), f.filename())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f.Reader); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// Sends data as JSON, or as multipart/form-data if there are files to upload.
+// The attachments field of data is filled in from the files.
+func (s *Session) requestWithFiles(ctx context.Context, method string, url string, data interface{}, files []File, v interface{}, opts ...RequestOption) error {
+	if len(files) == 0 {
+		return s.requestJSON(ctx, method, url, data, v, opts...)
+	}
+
+	payload, err := attachFiles(data, files)
+	if err != nil {
+		return err
+	}
+	recvBody, err := s.request(ctx, method, url, multipartBody(payload, files), opts...)
+	if err != nil {
+		return err
+	}
+	return unmarshalResponse(recvBody, v)
+}
+
+// Marshals data with an attachments entry for every file
+func attachFiles(data interface{}, files []File) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %v", err)
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("error marshaling request: %v", err)
+	}
+
+	var attachments []json.RawMessage
+	if existing, ok := fields["attachments"]; ok {
+		// Keep attachments already on the message when editing
+		if err := json.Unmarshal(existing, &attachments); err != nil {
+			return nil, fmt.Errorf("error marshaling request: %v", err)
+		}
+	}
+	for i, f := range files {
+		attachment, err := json.Marshal(partialAttachment{ID: i, Filename: f.filename(), Description: f.Description})
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	if fields["attachments"], err = json.Marshal(attachments); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,47 @@
+package discordgowrap
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMultipartBodyResend(t *testing.T) {
+	content := strings.Repeat("file content ", 10000)
+	body := multipartBody([]byte(`{}`), []File{{Name: "a.txt", Reader: strings.NewReader(content)}})
+
+	// The first attempt is abandoned part way through, like a request that got a 429
+	first, _, err := body()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(first, make([]byte, 1024)); err != nil {
+		t.Fatal(err)
+	}
+
+	second, _, err := body()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte(content)) {
+		t.Error("resent body doesn't contain the whole file")
+	}
+}
+
+func TestMultipartBodyNotReplayable(t *testing.T) {
+	body := multipartBody([]byte(`{}`), []File{{Name: "a.txt", Reader: io.MultiReader(strings.NewReader("x"))}})
+	first, _, err := body()
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(first)
+	if _, _, err := body(); !errors.Is(err, errBodyNotReplayable) {
+		t.Errorf("got %v, want errBodyNotReplayable", err)
+	}
+}
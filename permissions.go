@@ -0,0 +1,221 @@
+// Handles permissions
+package discordgowrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// A permission bitset, sent by Discord as a string
+type Permissions uint64
+
+// https://discord.com/developers/docs/topics/permissions#permissions-bitwise-permission-flags
+const (
+	PermissionCreateInstantInvite              Permissions = 1 << 0
+	PermissionKickMembers                      Permissions = 1 << 1
+	PermissionBanMembers                       Permissions = 1 << 2
+	PermissionAdministrator                    Permissions = 1 << 3
+	PermissionManageChannels                   Permissions = 1 << 4
+	PermissionManageGuild                      Permissions = 1 << 5
+	PermissionAddReactions                     Permissions = 1 << 6
+	PermissionViewAuditLog                     Permissions = 1 << 7
+	PermissionPrioritySpeaker                  Permissions = 1 << 8
+	PermissionStream                           Permissions = 1 << 9
+	PermissionViewChannel                      Permissions = 1 << 10
+	PermissionSendMessages                     Permissions = 1 << 11
+	PermissionSendTTSMessages                  Permissions = 1 << 12
+	PermissionManageMessages                   Permissions = 1 << 13
+	PermissionEmbedLinks                       Permissions = 1 << 14
+	PermissionAttachFiles                      Permissions = 1 << 15
+	PermissionReadMessageHistory               Permissions = 1 << 16
+	PermissionMentionEveryone                  Permissions = 1 << 17
+	PermissionUseExternalEmojis                Permissions = 1 << 18
+	PermissionViewGuildInsights                Permissions = 1 << 19
+	PermissionConnect                          Permissions = 1 << 20
+	PermissionSpeak                            Permissions = 1 << 21
+	PermissionMuteMembers                      Permissions = 1 << 22
+	PermissionDeafenMembers                    Permissions = 1 << 23
+	PermissionMoveMembers                      Permissions = 1 << 24
+	PermissionUseVAD                           Permissions = 1 << 25
+	PermissionChangeNickname                   Permissions = 1 << 26
+	PermissionManageNicknames                  Permissions = 1 << 27
+	PermissionManageRoles                      Permissions = 1 << 28
+	PermissionManageWebhooks                   Permissions = 1 << 29
+	PermissionManageGuildExpressions           Permissions = 1 << 30
+	PermissionUseApplicationCommands           Permissions = 1 << 31
+	PermissionRequestToSpeak                   Permissions = 1 << 32
+	PermissionManageEvents                     Permissions = 1 << 33
+	PermissionManageThreads                    Permissions = 1 << 34
+	PermissionCreatePublicThreads              Permissions = 1 << 35
+	PermissionCreatePrivateThreads             Permissions = 1 << 36
+	PermissionUseExternalStickers              Permissions = 1 << 37
+	PermissionSendMessagesInThreads            Permissions = 1 << 38
+	PermissionUseEmbeddedActivities            Permissions = 1 << 39
+	PermissionModerateMembers                  Permissions = 1 << 40
+	PermissionViewCreatorMonetizationAnalytics Permissions = 1 << 41
+	PermissionUseSoundboard                    Permissions = 1 << 42
+	PermissionCreateGuildExpressions           Permissions = 1 << 43
+	PermissionCreateEvents                     Permissions = 1 << 44
+	PermissionUseExternalSounds                Permissions = 1 << 45
+	PermissionSendVoiceMessages                Permissions = 1 << 46
+	PermissionSendPolls                        Permissions = 1 << 49
+	PermissionUseExternalApps                  Permissions = 1 << 50
+	PermissionPinMessages                      Permissions = 1 << 51
+	PermissionBypassSlowmode                   Permissions = 1 << 52
+
+	PermissionAll = PermissionCreateInstantInvite | PermissionKickMembers | PermissionBanMembers |
+		PermissionAdministrator | PermissionManageChannels | PermissionManageGuild | PermissionAddReactions |
+		PermissionViewAuditLog | PermissionPrioritySpeaker | PermissionStream | PermissionViewChannel |
+		PermissionSendMessages | PermissionSendTTSMessages | PermissionManageMessages | PermissionEmbedLinks |
+		PermissionAttachFiles | PermissionReadMessageHistory | PermissionMentionEveryone |
+		PermissionUseExternalEmojis | PermissionViewGuildInsights | PermissionConnect | PermissionSpeak |
+		PermissionMuteMembers | PermissionDeafenMembers | PermissionMoveMembers | PermissionUseVAD |
+		PermissionChangeNickname | PermissionManageNicknames | PermissionManageRoles | PermissionManageWebhooks |
+		PermissionManageGuildExpressions | PermissionUseApplicationCommands | PermissionRequestToSpeak |
+		PermissionManageEvents | PermissionManageThreads | PermissionCreatePublicThreads |
+		PermissionCreatePrivateThreads | PermissionUseExternalStickers | PermissionSendMessagesInThreads |
+		PermissionUseEmbeddedActivities | PermissionModerateMembers | PermissionViewCreatorMonetizationAnalytics |
+		PermissionUseSoundboard | PermissionCreateGuildExpressions | PermissionCreateEvents |
+		PermissionUseExternalSounds | PermissionSendVoiceMessages | PermissionSendPolls | PermissionUseExternalApps |
+		PermissionPinMessages | PermissionBypassSlowmode
+)
+
+// Returns whether all the given permissions are set
+func (p Permissions) Has(perms Permissions) bool {
+	return p&perms == perms
+}
+
+func (p Permissions) String() string {
+	return strconv.FormatUint(uint64(p), 10)
+}
+
+func (p Permissions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		// Older payloads send permissions as a number
+		var n uint64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		*p = Permissions(n)
+		return nil
+	}
+	if s == "" {
+		*p = 0
+		return nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*p = Permissions(n)
+	return nil
+}
+
+// https://discord.com/developers/docs/topics/permissions#permission-overwrites
+// Returns the guild wide permissions of a member from their roles, before channel overwrites.
+// roles are all roles of the guild, including @everyone whose ID is the guild ID.
+func ComputeBasePermissions(guildID string, ownerID string, userID string, memberRoles []string, roles []Role) Permissions {
+	if userID == ownerID {
+		return PermissionAll
+	}
+
+	byID := make(map[string]Permissions, len(roles))
+	for _, role := range roles {
+		byID[role.ID] = role.Permissions
+	}
+
+	permissions := byID[guildID]
+	for _, roleID := range memberRoles {
+		permissions |= byID[roleID]
+	}
+	if permissions.Has(PermissionAdministrator) {
+		return PermissionAll
+	}
+	return permissions
+}
+
+// Removed along with PermissionSendMessages, a member who can't send messages can't use them either
+const sendMessagesPermissions = PermissionMentionEveryone | PermissionSendTTSMessages | PermissionAttachFiles | PermissionEmbedLinks
+
+// Applies the overwrites of a channel to base permissions, in Discord's order:
+// @everyone, then all of the member's roles together, then the member.
+// Without PermissionViewChannel the member has no permissions in the channel,
+// and without PermissionSendMessages the permissions that depend on it are removed.
+func ComputeOverwrites(base Permissions, guildID string, userID string, memberRoles []string, overwrites []PermissionOverwrite) Permissions {
+	if base.Has(PermissionAdministrator) {
+		return PermissionAll
+	}
+
+	permissions := base
+	for _, o := range overwrites {
+		if o.Type == OverwriteTypeRole && o.ID == guildID {
+			permissions &^= o.Deny
+			permissions |= o.Allow
+			break
+		}
+	}
+
+	hasRole := make(map[string]bool, len(memberRoles))
+	for _, roleID := range memberRoles {
+		hasRole[roleID] = true
+	}
+	var allow, deny Permissions
+	for _, o := range overwrites {
+		if o.Type == OverwriteTypeRole && o.ID != guildID && hasRole[o.ID] {
+			allow |= o.Allow
+			deny |= o.Deny
+		}
+	}
+	permissions &^= deny
+	permissions |= allow
+
+	for _, o := range overwrites {
+		if o.Type == OverwriteTypeMember && o.ID == userID {
+			permissions &^= o.Deny
+			permissions |= o.Allow
+			break
+		}
+	}
+
+	if !permissions.Has(PermissionViewChannel) {
+		return 0
+	}
+	if !permissions.Has(PermissionSendMessages) {
+		permissions &^= sendMessagesPermissions
+	}
+	return permissions
+}
+
+// Restricts the permissions of a timed out member (see Member.TimedOut) to
+// PermissionViewChannel and PermissionReadMessageHistory. The owner and
+// administrators are not affected by timeouts.
+func ApplyTimeout(permissions Permissions) Permissions {
+	if permissions.Has(PermissionAdministrator) {
+		return permissions
+	}
+	return permissions & (PermissionViewChannel | PermissionReadMessageHistory)
+}
+
+// Returns the effective permissions of a member in a channel.
+// For threads, pass the overwrites of the parent channel.
+// Timeouts aren't known here, pass the result to ApplyTimeout for timed out members.
+func ComputePermissions(guildID string, ownerID string, userID string, memberRoles []string, roles []Role, overwrites []PermissionOverwrite) Permissions {
+	base := ComputeBasePermissions(guildID, ownerID, userID, memberRoles, roles)
+	return ComputeOverwrites(base, guildID, userID, memberRoles, overwrites)
+}
+
+// Returned when the bot is missing permissions it needs in a channel
+type PermissionError struct {
+	ChannelID string
+	Missing   Permissions
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("missing permissions %s in channel %s", e.Missing, e.ChannelID)
+}
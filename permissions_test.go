@@ -0,0 +1,183 @@
+package discordgowrap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestComputePermissions(t *testing.T) {
+	const (
+		guildID = "100"
+		ownerID = "1"
+		userID  = "2"
+		modRole = "200"
+		muted   = "201"
+	)
+	everyone := PermissionViewChannel | PermissionSendMessages | PermissionConnect
+	roles := []Role{
+		{ID: guildID, Permissions: everyone},
+		{ID: modRole, Permissions: PermissionKickMembers | PermissionManageMessages},
+		{ID: muted, Permissions: 0},
+		{ID: "202", Permissions: PermissionAdministrator},
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		memberRoles []string
+		overwrites  []PermissionOverwrite
+		timedOut    bool
+		want        Permissions
+	}{
+		{
+			name: "everyone role only",
+			want: everyone,
+		},
+		{
+			name:        "roles are combined",
+			memberRoles: []string{modRole},
+			want:        everyone | PermissionKickMembers | PermissionManageMessages,
+		},
+		{
+			name:   "owner has everything",
+			userID: ownerID,
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: OverwriteTypeRole, Deny: PermissionViewChannel},
+			},
+			want: PermissionAll,
+		},
+		{
+			name:        "administrator ignores overwrites",
+			memberRoles: []string{"202"},
+			overwrites: []PermissionOverwrite{
+				{ID: userID, Type: OverwriteTypeMember, Deny: PermissionSendMessages},
+			},
+			want: PermissionAll,
+		},
+		{
+			name: "everyone overwrite",
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: OverwriteTypeRole, Deny: PermissionConnect, Allow: PermissionAddReactions},
+			},
+			want: PermissionViewChannel | PermissionSendMessages | PermissionAddReactions,
+		},
+		{
+			name:        "role allow wins over role deny",
+			memberRoles: []string{modRole, muted},
+			overwrites: []PermissionOverwrite{
+				{ID: muted, Type: OverwriteTypeRole, Deny: PermissionSendMessages},
+				{ID: modRole, Type: OverwriteTypeRole, Allow: PermissionSendMessages},
+			},
+			want: everyone | PermissionKickMembers | PermissionManageMessages,
+		},
+		{
+			name:        "role overwrite beats everyone overwrite",
+			memberRoles: []string{modRole},
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: OverwriteTypeRole, Deny: PermissionViewChannel},
+				{ID: modRole, Type: OverwriteTypeRole, Allow: PermissionViewChannel},
+			},
+			want: everyone | PermissionKickMembers | PermissionManageMessages,
+		},
+		{
+			name:        "overwrites for roles the member lacks are ignored",
+			memberRoles: []string{},
+			overwrites: []PermissionOverwrite{
+				{ID: muted, Type: OverwriteTypeRole, Deny: PermissionSendMessages},
+			},
+			want: everyone,
+		},
+		{
+			name:        "member overwrite is applied last",
+			memberRoles: []string{muted},
+			overwrites: []PermissionOverwrite{
+				{ID: muted, Type: OverwriteTypeRole, Deny: PermissionSendMessages | PermissionConnect},
+				{ID: userID, Type: OverwriteTypeMember, Allow: PermissionSendMessages},
+			},
+			want: PermissionViewChannel | PermissionSendMessages,
+		},
+		{
+			name: "member overwrite for another user is ignored",
+			overwrites: []PermissionOverwrite{
+				{ID: "3", Type: OverwriteTypeMember, Deny: PermissionViewChannel},
+			},
+			want: everyone,
+		},
+		{
+			name:        "no view channel removes everything",
+			memberRoles: []string{modRole},
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: OverwriteTypeRole, Deny: PermissionViewChannel},
+			},
+			want: 0,
+		},
+		{
+			name: "no send messages removes permissions that depend on it",
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: OverwriteTypeRole, Deny: PermissionSendMessages, Allow: PermissionAttachFiles | PermissionEmbedLinks | PermissionMentionEveryone | PermissionSendTTSMessages | PermissionAddReactions},
+			},
+			want: PermissionViewChannel | PermissionConnect | PermissionAddReactions,
+		},
+		{
+			name: "timed out member can only view and read history",
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: OverwriteTypeRole, Allow: PermissionReadMessageHistory},
+			},
+			timedOut: true,
+			want:     PermissionViewChannel | PermissionReadMessageHistory,
+		},
+		{
+			name:        "timeout doesn't affect administrators",
+			memberRoles: []string{"202"},
+			timedOut:    true,
+			want:        PermissionAll,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid := tt.userID
+			if uid == "" {
+				uid = userID
+			}
+			got := ComputePermissions(guildID, ownerID, uid, tt.memberRoles, roles, tt.overwrites)
+			if tt.timedOut {
+				got = ApplyTimeout(got)
+			}
+			if got != tt.want {
+				t.Errorf("ComputePermissions() = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionsJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Permissions
+	}{
+		{`"0"`, 0},
+		{`"2048"`, PermissionSendMessages},
+		{`"1125899906842624"`, PermissionUseExternalApps},
+		{`"4503599627370496"`, PermissionBypassSlowmode},
+		{`8`, PermissionAdministrator},
+		{`""`, 0},
+	}
+	for _, tt := range tests {
+		var got Permissions
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+			t.Fatalf("Unmarshal(%s) error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	data, err := json.Marshal(PermissionSendMessages | PermissionViewChannel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"3072"` {
+		t.Errorf("Marshal() = %s, want \"3072\"", data)
+	}
+}
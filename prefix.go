@@ -0,0 +1,499 @@
+// Handles text commands like "!ping" in messages
+package discordgowrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+type PrefixHandler func(c *PrefixContext) error
+
+// A text command, registered with PrefixRouter.Add
+type PrefixCommand struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Usage       string        // the arguments shown in help, e.g. "<user> [reason]"
+	Cooldown    time.Duration // how often a user can run the command, 0 for no limit
+	Handler     PrefixHandler
+
+	mutex    sync.Mutex
+	lastUsed map[string]time.Time // by user ID
+}
+
+// Returns a CooldownError if the user ran the command less than Cooldown ago
+func (cmd *PrefixCommand) checkCooldown(userID string) error {
+	if cmd.Cooldown <= 0 {
+		return nil
+	}
+	cmd.mutex.Lock()
+	defer cmd.mutex.Unlock()
+
+	now := time.Now()
+	if last, exists := cmd.lastUsed[userID]; exists && now.Sub(last) < cmd.Cooldown {
+		return &CooldownError{Remaining: cmd.Cooldown - now.Sub(last)}
+	}
+	if cmd.lastUsed == nil {
+		cmd.lastUsed = make(map[string]time.Time)
+	}
+	cmd.lastUsed[userID] = now
+	// Forget expired entries so the map doesn't grow forever
+	for id, t := range cmd.lastUsed {
+		if now.Sub(t) >= cmd.Cooldown {
+			delete(cmd.lastUsed, id)
+		}
+	}
+	return nil
+}
+
+// Passed to prefix command handlers, it can be used as the context for REST calls
+type PrefixContext struct {
+	context.Context
+	Session *Session
+	Message *MessageCreate
+	Command *PrefixCommand
+	Prefix  string   // the prefix the command was invoked with
+	Args    []string // the arguments after the command name, split like a shell would
+}
+
+// Replies to the command message
+func (c *PrefixContext) Reply(content string) error {
+	data := MessageSend{Content: content}
+	data.Reply(c.Message.ChannelID, c.Message.ID)
+	_, err := c.Session.SendMessageComplex(c, c.Message.ChannelID, data)
+	return err
+}
+
+// Returned when an argument is missing or can't be converted
+type ArgumentError struct {
+	Index int
+	Err   error
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("argument %d: %v", e.Index+1, e.Err)
+}
+
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+var ErrMissingArgument = errors.New("missing argument")
+
+// Returns the argument at index, or an ArgumentError if there are fewer arguments
+func (c *PrefixContext) Arg(index int) (string, error) {
+	if index < 0 || index >= len(c.Args) {
+		return "", &ArgumentError{Index: index, Err: ErrMissingArgument}
+	}
+	return c.Args[index], nil
+}
+
+// Returns the arguments from index on joined by spaces, e.g. for a reason
+func (c *PrefixContext) Rest(index int) string {
+	if index < 0 || index >= len(c.Args) {
+		return ""
+	}
+	return strings.Join(c.Args[index:], " ")
+}
+
+func (c *PrefixContext) Int(index int) (int64, error) {
+	arg, err := c.Arg(index)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, &ArgumentError{Index: index, Err: fmt.Errorf("%q is not a whole number", arg)}
+	}
+	return n, nil
+}
+
+// Parses a duration like "90s", "1h30m" or "7d". Days ("d") and weeks ("w") are
+// supported in addition to the units of time.ParseDuration.
+func (c *PrefixContext) Duration(index int) (time.Duration, error) {
+	arg, err := c.Arg(index)
+	if err != nil {
+		return 0, err
+	}
+	d, err := ParseDuration(arg)
+	if err != nil {
+		return 0, &ArgumentError{Index: index, Err: err}
+	}
+	return d, nil
+}
+
+// Like time.ParseDuration but also accepts days ("d") and weeks ("w")
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%q is not a duration", s)
+	}
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		// Each part is a number followed by a unit
+		end := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+		if end <= 0 {
+			return 0, fmt.Errorf("%q is not a duration", s)
+		}
+		unitEnd := strings.IndexFunc(rest[end:], func(r rune) bool { return unicode.IsDigit(r) || r == '.' })
+		if unitEnd < 0 {
+			unitEnd = len(rest) - end
+		}
+		number, unit := rest[:end], rest[end:end+unitEnd]
+		rest = rest[end+unitEnd:]
+
+		var multiplier time.Duration
+		switch unit {
+		case "d":
+			multiplier = 24 * time.Hour
+		case "w":
+			multiplier = 7 * 24 * time.Hour
+		default:
+			d, err := time.ParseDuration(number + unit)
+			if err != nil {
+				return 0, fmt.Errorf("%q is not a duration", s)
+			}
+			total += d
+			continue
+		}
+		f, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration", s)
+		}
+		total += time.Duration(f * float64(multiplier))
+	}
+	return total, nil
+}
+
+// Returns the ID in a mention like "<@123>", or the argument itself if it is an ID
+func parseMention(arg string, prefixes ...string) (string, bool) {
+	if isSnowflake(arg) {
+		return arg, true
+	}
+	if !strings.HasPrefix(arg, "<") || !strings.HasSuffix(arg, ">") {
+		return "", false
+	}
+	inner := arg[1 : len(arg)-1]
+	for _, prefix := range prefixes {
+		if id, found := strings.CutPrefix(inner, prefix); found && isSnowflake(id) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// Converts a user mention or ID. Mentioned users come with the message, other users are fetched.
+func (c *PrefixContext) User(index int) (*User, error) {
+	arg, err := c.Arg(index)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := parseMention(arg, "@!", "@")
+	if !ok {
+		return nil, &ArgumentError{Index: index, Err: fmt.Errorf("%q is not a user", arg)}
+	}
+	for _, user := range c.Message.Mentions {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	user, err := c.Session.GetUser(c, id)
+	if err != nil {
+		return nil, &ArgumentError{Index: index, Err: fmt.Errorf("unknown user %s: %v", id, err)}
+	}
+	return user, nil
+}
+
+// Converts a channel mention or ID
+func (c *PrefixContext) Channel(index int) (*Channel, error) {
+	arg, err := c.Arg(index)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := parseMention(arg, "#")
+	if !ok {
+		return nil, &ArgumentError{Index: index, Err: fmt.Errorf("%q is not a channel", arg)}
+	}
+	channel, err := c.Session.GetChannel(c, id)
+	if err != nil {
+		return nil, &ArgumentError{Index: index, Err: fmt.Errorf("unknown channel %s: %v", id, err)}
+	}
+	return channel, nil
+}
+
+// Converts a role mention, ID or name (case insensitive) of the guild the message was sent in
+func (c *PrefixContext) Role(index int) (*Role, error) {
+	arg, err := c.Arg(index)
+	if err != nil {
+		return nil, err
+	}
+	if c.Message.GuildID == "" {
+		return nil, &ArgumentError{Index: index, Err: errors.New("roles can only be used in servers")}
+	}
+	roles, err := c.Session.GuildRoles(c, c.Message.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	id, isMention := parseMention(arg, "@&")
+	for _, role := range roles {
+		if (isMention && role.ID == id) || strings.EqualFold(role.Name, arg) {
+			return &role, nil
+		}
+	}
+	return nil, &ArgumentError{Index: index, Err: fmt.Errorf("unknown role %q", arg)}
+}
+
+// Splits a string into arguments like a shell would. Whitespace separates arguments,
+// unless it is quoted with " or ', and a backslash escapes the next character.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// Routes messages starting with a prefix to text commands
+type PrefixRouter struct {
+	session *Session
+
+	mutex    sync.RWMutex
+	commands map[string]*PrefixCommand // by lowercase name and aliases
+	list     []*PrefixCommand          // in the order added, for help
+	prefixes map[string]string         // by guild ID
+
+	DefaultPrefix string
+	MentionPrefix bool // also accept "@Bot command", true by default
+
+	// Called when a handler returns an error, logs it by default
+	OnError func(c *PrefixContext, err error)
+}
+
+// Creates a prefix router that handles MESSAGE_CREATE events of the session.
+// Reading message content requires IntentMessageContent.
+func NewPrefixRouter(s *Session, defaultPrefix string) *PrefixRouter {
+	r := &PrefixRouter{
+		session:       s,
+		commands:      make(map[string]*PrefixCommand),
+		prefixes:      make(map[string]string),
+		DefaultPrefix: defaultPrefix,
+		MentionPrefix: true,
+	}
+	r.OnError = func(c *PrefixContext, err error) {
+		log.Printf("[Prefix] Command %s failed: %v\n", c.Command.Name, err)
+		// Tell the user what they did wrong
+		var cooldown *CooldownError
+		var argument *ArgumentError
+		if errors.As(err, &cooldown) || errors.As(err, &argument) {
+			message := err.Error()
+			if argument != nil && c.Command.Usage != "" {
+				message += fmt.Sprintf("\nUsage: `%s%s %s`", c.Prefix, c.Command.Name, c.Command.Usage)
+			}
+			if err := c.Reply(message); err != nil {
+				log.Printf("[Prefix] Failed to reply to %s: %v\n", c.Command.Name, err)
+			}
+		}
+	}
+	AddHandler(s, func(s *Session, e *MessageCreate) {
+		r.HandleMessage(context.Background(), e)
+	})
+	return r
+}
+
+// Registers a command, names and aliases are case insensitive
+func (r *PrefixRouter) Add(cmd *PrefixCommand) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		r.commands[strings.ToLower(name)] = cmd
+	}
+	r.list = append(r.list, cmd)
+}
+
+// Sets the prefix of a guild, an empty prefix resets it to DefaultPrefix
+func (r *PrefixRouter) SetGuildPrefix(guildID string, prefix string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if prefix == "" {
+		delete(r.prefixes, guildID)
+		return
+	}
+	r.prefixes[guildID] = prefix
+}
+
+// Returns the prefix used in a guild, or in DMs if guildID is empty
+func (r *PrefixRouter) Prefix(guildID string) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if prefix, exists := r.prefixes[guildID]; exists {
+		return prefix
+	}
+	return r.DefaultPrefix
+}
+
+// Returns the text after the prefix and the prefix that was used
+func (r *PrefixRouter) trimPrefix(guildID string, content string) (string, string, bool) {
+	if r.MentionPrefix && r.session.Bot.ID != "" {
+		for _, mention := range []string{"<@" + r.session.Bot.ID + ">", "<@!" + r.session.Bot.ID + ">"} {
+			if rest, found := strings.CutPrefix(content, mention); found {
+				return strings.TrimSpace(rest), mention + " ", true
+			}
+		}
+	}
+	prefix := r.Prefix(guildID)
+	if prefix == "" {
+		return "", "", false
+	}
+	rest, found := strings.CutPrefix(content, prefix)
+	return rest, prefix, found
+}
+
+// Runs the command in a message, if any. Used for gateway events by NewPrefixRouter.
+func (r *PrefixRouter) HandleMessage(ctx context.Context, msg *MessageCreate) {
+	if msg.Author.Bot {
+		return
+	}
+	rest, prefix, found := r.trimPrefix(msg.GuildID, msg.Content)
+	if !found {
+		return
+	}
+	// The name ends at any whitespace, "!ban\t@user" and "!help\nping" are commands too
+	rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	name, argString := rest, ""
+	if end := strings.IndexFunc(rest, unicode.IsSpace); end >= 0 {
+		name, argString = rest[:end], rest[end:]
+	}
+	r.mutex.RLock()
+	cmd, exists := r.commands[strings.ToLower(name)]
+	r.mutex.RUnlock()
+	if !exists {
+		return
+	}
+
+	c := &PrefixContext{
+		Context: ctx,
+		Session: r.session,
+		Message: msg,
+		Command: cmd,
+		Prefix:  prefix,
+	}
+	args, err := SplitArgs(argString)
+	if err == nil {
+		c.Args = args
+		err = cmd.checkCooldown(msg.Author.ID)
+	}
+	if err == nil {
+		err = cmd.Handler(c)
+	}
+	if err != nil && r.OnError != nil {
+		r.OnError(c, err)
+	}
+}
+
+// Returns a list of commands for a guild, or the details of one command if name is given
+func (r *PrefixRouter) Help(guildID string, name string) string {
+	prefix := r.Prefix(guildID)
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	describe := func(cmd *PrefixCommand) string {
+		line := "`" + prefix + cmd.Name
+		if cmd.Usage != "" {
+			line += " " + cmd.Usage
+		}
+		line += "`"
+		if cmd.Description != "" {
+			line += " - " + cmd.Description
+		}
+		return line
+	}
+
+	if name != "" {
+		cmd, exists := r.commands[strings.ToLower(name)]
+		if !exists {
+			return fmt.Sprintf("Unknown command %q", name)
+		}
+		help := describe(cmd)
+		if len(cmd.Aliases) > 0 {
+			help += "\nAliases: " + strings.Join(cmd.Aliases, ", ")
+		}
+		if cmd.Cooldown > 0 {
+			help += fmt.Sprintf("\nCooldown: %s", cmd.Cooldown)
+		}
+		return help
+	}
+
+	commands := slices.Clone(r.list)
+	slices.SortFunc(commands, func(a, b *PrefixCommand) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	lines := make([]string, 0, len(commands)+1)
+	lines = append(lines, "Commands:")
+	for _, cmd := range commands {
+		lines = append(lines, describe(cmd))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Adds a "help" command that replies with Help
+func (r *PrefixRouter) AddHelpCommand() {
+	r.Add(&PrefixCommand{
+		Name:        "help",
+		Aliases:     []string{"commands"},
+		Description: "Shows the commands, or details about one command",
+		Usage:       "[command]",
+		Handler: func(c *PrefixContext) error {
+			name := ""
+			if len(c.Args) > 0 {
+				name = c.Args[0]
+			}
+			return c.Reply(r.Help(c.Message.GuildID, name))
+		},
+	})
+}
@@ -0,0 +1,118 @@
+package discordgowrap
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "   ", want: nil},
+		{input: "a b  c", want: []string{"a", "b", "c"}},
+		{input: "a\tb\nc", want: []string{"a", "b", "c"}},
+		{input: `"hello world" again`, want: []string{"hello world", "again"}},
+		{input: `'single quoted' x`, want: []string{"single quoted", "x"}},
+		{input: `""`, want: []string{""}},
+		{input: `a"b c"d`, want: []string{"ab cd"}},
+		{input: `it\'s fine`, want: []string{"it's", "fine"}},
+		{input: `escaped\ space`, want: []string{"escaped space"}},
+		{input: `"say \"hi\""`, want: []string{`say "hi"`}},
+		{input: `'C:\path'`, want: []string{`C:\path`}},
+		{input: `"it's"`, want: []string{"it's"}},
+		{input: `"unterminated`, wantErr: true},
+		{input: `'unterminated`, wantErr: true},
+		{input: `trailing\`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := SplitArgs(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SplitArgs(%q) error = %v, want error %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SplitArgs(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "90s", want: 90 * time.Second},
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "500ms", want: 500 * time.Millisecond},
+		{input: "7d", want: 7 * 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "1.5d", want: 36 * time.Hour},
+		{input: "1w2d3h", want: 9*24*time.Hour + 3*time.Hour},
+		{input: "", wantErr: true},
+		{input: "10", wantErr: true},
+		{input: "1d2", wantErr: true},
+		{input: "h", wantErr: true},
+		{input: "5x", wantErr: true},
+		{input: "-5m", wantErr: true},
+		{input: "1..5d", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q) error = %v, want error %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixCommandName(t *testing.T) {
+	tests := []struct {
+		content string
+		name    string
+		args    []string
+	}{
+		{content: "!ping", name: "ping"},
+		{content: "!PING a b", name: "ping", args: []string{"a", "b"}},
+		{content: "!help\nping", name: "help", args: []string{"ping"}},
+		{content: "!ban\t<@123>", name: "ban", args: []string{"<@123>"}},
+		{content: "! ping", name: "ping"},
+		{content: "ping", name: ""},
+		{content: "!unknown", name: ""},
+	}
+	for _, tt := range tests {
+		r := NewPrefixRouter(&Session{}, "!")
+		var got *PrefixContext
+		for _, name := range []string{"ping", "help", "ban"} {
+			r.Add(&PrefixCommand{Name: name, Handler: func(c *PrefixContext) error {
+				got = c
+				return nil
+			}})
+		}
+
+		msg := &MessageCreate{}
+		msg.Content = tt.content
+		r.HandleMessage(t.Context(), msg)
+		if tt.name == "" {
+			if got != nil {
+				t.Errorf("%q ran %s, want no command", tt.content, got.Command.Name)
+			}
+			continue
+		}
+		if got == nil || got.Command.Name != tt.name {
+			t.Errorf("%q didn't run %s", tt.content, tt.name)
+			continue
+		}
+		if !slices.Equal(got.Args, tt.args) {
+			t.Errorf("%q args = %q, want %q", tt.content, got.Args, tt.args)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Handles REST rate limiting
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// https://discord.com/developers/docs/topics/rate-limits
+const (
+	globalRateLimit = 50 // requests per second across all routes
+
+	// Discord bans IPs for an hour after 10,000 invalid requests (401, 403 and 429)
+	// within 10 minutes. Stop a little before that.
+	invalidRequestWindow    = 10 * time.Minute
+	invalidRequestThreshold = 9500
+
+	// Every interaction and webhook token gets its own bucket, so idle buckets are
+	// removed once they have reset to keep the map from growing forever
+	bucketSweepInterval = time.Minute
+)
+
+var ErrInvalidRequestLimit = errors.New("too many invalid requests, refusing to send to avoid a Cloudflare ban")
+
+type rateLimiter struct {
+	sync.Mutex
+	hashes  map[string]string  // route -> bucket hash learned from X-RateLimit-Bucket
+	buckets map[string]*bucket // "route:major" or "hash:major" -> bucket
+	swept   time.Time          // last time idle buckets were removed
+
+	globalMutex  sync.Mutex
+	globalReset  time.Time // set when Discord reports X-RateLimit-Global
+	windowStart  time.Time
+	windowCount  int
+	invalidTimes []time.Time // 401/403/429 responses within invalidRequestWindow
+}
+
+type bucket struct {
+	lock      chan struct{} // held while a request to the bucket is in flight
+	remaining int           // -1 until Discord tells us otherwise
+	reset     time.Time
+	lastUsed  time.Time // guarded by the rateLimiter mutex
+}
+
+func (b *bucket) unlock() {
+	<-b.lock
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		hashes:  make(map[string]string),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Either gets the bucket for a route or creates a new one if it doesn't exist.
+// Routes sharing a bucket hash share the same bucket for the same major parameter,
+// routes without a known hash get one bucket per major parameter.
+func (r *rateLimiter) getBucket(route string, major string) *bucket {
+	r.Lock()
+	defer r.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.swept) >= bucketSweepInterval {
+		r.sweep(now)
+	}
+
+	key := route + ":" + major
+	if hash, exists := r.hashes[route]; exists {
+		key = hash + ":" + major
+	}
+	b, exists := r.buckets[key]
+	if !exists {
+		b = &bucket{lock: make(chan struct{}, 1), remaining: -1}
+		r.buckets[key] = b
+	}
+	b.lastUsed = now
+	return b
+}
+
+// Removes buckets that have reset and weren't used since the last sweep, the caller must hold the lock
+func (r *rateLimiter) sweep(now time.Time) {
+	r.swept = now
+	for key, b := range r.buckets {
+		if now.Sub(b.lastUsed) < bucketSweepInterval {
+			continue
+		}
+		// Buckets with a request in flight are skipped
+		select {
+		case b.lock <- struct{}{}:
+		default:
+			continue
+		}
+		if now.After(b.reset) {
+			delete(r.buckets, key)
+		}
+		b.unlock()
+	}
+}
+
+// Locks the bucket for a route, waiting until it has requests remaining.
+// The caller must unlock the bucket once the response has been processed.
+func (r *rateLimiter) lockBucket(ctx context.Context, route string, major string) (*bucket, error) {
+	b := r.getBucket(route, major)
+	select {
+	case b.lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if b.remaining == 0 {
+		if err := sleepContext(ctx, time.Until(b.reset)); err != nil {
+			b.unlock()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Updates the bucket from the X-RateLimit-* headers of a response
+func (r *rateLimiter) update(route string, major string, b *bucket, header http.Header) {
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		b.remaining = remaining
+	}
+	if resetAfter, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		b.reset = time.Now().Add(time.Duration(resetAfter * float64(time.Second)))
+	}
+
+	hash := header.Get("X-RateLimit-Bucket")
+	if hash == "" {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if r.hashes[route] == hash {
+		return
+	}
+	r.hashes[route] = hash
+	key := hash + ":" + major
+	if _, exists := r.buckets[key]; !exists {
+		r.buckets[key] = b
+	}
+}
+
+// Waits until a request can be sent without exceeding the global rate limit
+func (r *rateLimiter) waitGlobal(ctx context.Context) error {
+	for {
+		r.globalMutex.Lock()
+		now := time.Now()
+		var wait time.Duration
+		if now.Before(r.globalReset) {
+			wait = r.globalReset.Sub(now)
+		} else {
+			if now.Sub(r.windowStart) >= time.Second {
+				r.windowStart = now
+				r.windowCount = 0
+			}
+			if r.windowCount < globalRateLimit {
+				r.windowCount++
+				r.globalMutex.Unlock()
+				return nil
+			}
+			wait = r.windowStart.Add(time.Second).Sub(now)
+		}
+		r.globalMutex.Unlock()
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Blocks all requests until the global rate limit resets
+func (r *rateLimiter) setGlobalReset(wait time.Duration) {
+	r.globalMutex.Lock()
+	defer r.globalMutex.Unlock()
+	r.globalReset = time.Now().Add(wait)
+}
+
+// Records a 401, 403 or 429 response
+func (r *rateLimiter) recordInvalid() {
+	r.globalMutex.Lock()
+	defer r.globalMutex.Unlock()
+	r.invalidTimes = append(r.invalidTimes, time.Now())
+}
+
+// Returns an error if too many invalid requests were sent in the last 10 minutes
+func (r *rateLimiter) checkInvalid() error {
+	r.globalMutex.Lock()
+	defer r.globalMutex.Unlock()
+	cutoff := time.Now().Add(-invalidRequestWindow)
+	expired := 0
+	for expired < len(r.invalidTimes) && r.invalidTimes[expired].Before(cutoff) {
+		expired++
+	}
+	r.invalidTimes = r.invalidTimes[expired:]
+	if len(r.invalidTimes) >= invalidRequestThreshold {
+		return ErrInvalidRequestLimit
+	}
+	return nil
+}
+
+// Returns whether a response counts towards the invalid request limit.
+// 429s with a shared scope are not counted by Discord.
+func isInvalidResponse(status int, header http.Header) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	case http.StatusTooManyRequests:
+		return header.Get("X-RateLimit-Scope") != "shared"
+	}
+	return false
+}
+
+// Returns how long to wait before retrying a 429 response
+func retryAfter(header http.Header, body []byte) time.Duration {
+	if seconds, err := strconv.ParseFloat(header.Get("Retry-After"), 64); err == nil {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	var rl struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if err := json.Unmarshal(body, &rl); err == nil && rl.RetryAfter > 0 {
+		return time.Duration(rl.RetryAfter * float64(time.Second))
+	}
+	return time.Second
+}
+
+// Returns the rate limit route for a request, e.g. "GET /channels/:channel/messages/:id".
+// IDs are replaced with placeholders so the hashes map stays one entry per route,
+// major parameters are added to the bucket key separately.
+func routeKey(method string, path string) string {
+	path, _, _ = strings.Cut(strings.TrimPrefix(path, apiBase), "?")
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		// Webhook and interaction tokens are part of the major parameter
+		if i >= 2 && (parts[i-2] == "webhooks" || parts[i-2] == "interactions") {
+			parts[i] = ":token"
+			continue
+		}
+		switch parts[i-1] {
+		case "channels":
+			parts[i] = ":channel"
+			continue
+		case "guilds":
+			parts[i] = ":guild"
+			continue
+		case "webhooks":
+			parts[i] = ":webhook"
+			continue
+		case "reactions":
+			parts[i] = ":emoji"
+			continue
+		}
+		if isSnowflake(parts[i]) {
+			parts[i] = ":id"
+		}
+	}
+	return method + " " + strings.Join(parts, "/")
+}
+
+// Returns the major parameter of a request path, e.g. the channel ID for
+// "/channels/123/messages". Webhooks and interactions also include their token.
+func majorParameter(path string) string {
+	path, _, _ = strings.Cut(strings.TrimPrefix(path, apiBase), "?")
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	switch parts[0] {
+	case "channels", "guilds":
+		return parts[1]
+	case "webhooks", "interactions":
+		if len(parts) >= 3 {
+			return parts[1] + "/" + parts[2]
+		}
+		return parts[1]
+	}
+	return ""
+}
+
+// Sleeps for the given duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isSnowflake(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
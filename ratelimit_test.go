@@ -0,0 +1,77 @@
+package discordgowrap
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRouteKey(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		route  string
+		major  string
+	}{
+		{"GET", apiBase + "/channels/123/messages?limit=100", "GET /channels/:channel/messages", "123"},
+		{"DELETE", apiBase + "/channels/123/messages/456", "DELETE /channels/:channel/messages/:id", "123"},
+		{"PUT", apiBase + "/channels/123/messages/456/reactions/%F0%9F%91%8D/@me", "PUT /channels/:channel/messages/:id/reactions/:emoji/@me", "123"},
+		{"PATCH", apiBase + "/guilds/789/members/456", "PATCH /guilds/:guild/members/:id", "789"},
+		{"GET", apiBase + "/users/@me", "GET /users/@me", ""},
+		{"POST", apiBase + "/webhooks/111/secret-token?wait=true", "POST /webhooks/:webhook/:token", "111/secret-token"},
+		{"PATCH", apiBase + "/webhooks/111/secret-token/messages/@original", "PATCH /webhooks/:webhook/:token/messages/@original", "111/secret-token"},
+		{"POST", apiBase + "/interactions/222/interaction-token/callback", "POST /interactions/:id/:token/callback", "222/interaction-token"},
+	}
+	for _, tt := range tests {
+		if route := routeKey(tt.method, tt.path); route != tt.route {
+			t.Errorf("routeKey(%s, %s) = %q, want %q", tt.method, tt.path, route, tt.route)
+		}
+		if major := majorParameter(tt.path); major != tt.major {
+			t.Errorf("majorParameter(%s) = %q, want %q", tt.path, major, tt.major)
+		}
+	}
+}
+
+func TestBucketsPerMajorParameter(t *testing.T) {
+	r := newRateLimiter()
+	route := "POST /interactions/:id/:token/callback"
+
+	first := r.getBucket(route, "1/a")
+	if second := r.getBucket(route, "2/b"); first == second {
+		t.Fatal("interactions with different tokens share a bucket before a hash is known")
+	}
+	if again := r.getBucket(route, "1/a"); first != again {
+		t.Fatal("the same interaction got a different bucket")
+	}
+}
+
+func TestIdleBucketsAreRemoved(t *testing.T) {
+	r := newRateLimiter()
+	r.getBucket("GET /channels/:channel/messages", "1")
+	busy := r.getBucket("GET /channels/:channel/messages", "2")
+	busy.lock <- struct{}{}
+	defer busy.unlock()
+
+	r.sweep(time.Now().Add(2 * bucketSweepInterval))
+	if _, exists := r.buckets["GET /channels/:channel/messages:1"]; exists {
+		t.Error("idle bucket was kept")
+	}
+	if _, exists := r.buckets["GET /channels/:channel/messages:2"]; !exists {
+		t.Error("bucket with a request in flight was removed")
+	}
+}
+
+func TestHashesPerRouteTemplate(t *testing.T) {
+	r := newRateLimiter()
+	header := http.Header{"X-Ratelimit-Bucket": {"abc"}}
+	for _, path := range []string{"/channels/1/messages", "/channels/2/messages"} {
+		route := routeKey("GET", apiBase+path)
+		r.update(route, majorParameter(path), r.getBucket(route, majorParameter(path)), header)
+	}
+	if len(r.hashes) != 1 {
+		t.Fatalf("got %d hashes for one route, want 1", len(r.hashes))
+	}
+	if first, second := r.getBucket("GET /channels/:channel/messages", "1"), r.getBucket("GET /channels/:channel/messages", "2"); first == second {
+		t.Fatal("channels sharing a bucket hash share a bucket")
+	}
+}
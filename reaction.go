@@ -0,0 +1,132 @@
+// Handles message reactions
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// https://discord.com/developers/docs/resources/message#get-reactions-reaction-types
+const (
+	ReactionTypeNormal = 0
+	ReactionTypeBurst  = 1 // Super reactions
+)
+
+// https://discord.com/developers/docs/resources/message#reaction-object
+type Reaction struct {
+	Count   int   `json:"count"`
+	Me      bool  `json:"me"`
+	MeBurst bool  `json:"me_burst"`
+	Emoji   Emoji `json:"emoji"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-add
+type MessageReactionAdd struct {
+	UserID          string `json:"user_id"`
+	ChannelID       string `json:"channel_id"`
+	MessageID       string `json:"message_id"`
+	GuildID         string `json:"guild_id,omitempty"`
+	Emoji           Emoji  `json:"emoji"`
+	MessageAuthorID string `json:"message_author_id,omitempty"`
+	Burst           bool   `json:"burst"`
+	Type            int    `json:"type"`
+}
+
+func (*MessageReactionAdd) EventType() string { return TypeMessageReactionAdd }
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-remove
+type MessageReactionRemove struct {
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+	GuildID   string `json:"guild_id,omitempty"`
+	Emoji     Emoji  `json:"emoji"`
+	Burst     bool   `json:"burst"`
+	Type      int    `json:"type"`
+}
+
+func (*MessageReactionRemove) EventType() string { return TypeMessageReactionRemove }
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-remove-all
+type MessageReactionRemoveAll struct {
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+	GuildID   string `json:"guild_id,omitempty"`
+}
+
+func (*MessageReactionRemoveAll) EventType() string { return TypeMessageReactionRemoveAll }
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-remove-emoji
+type MessageReactionRemoveEmoji struct {
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+	GuildID   string `json:"guild_id,omitempty"`
+	Emoji     Emoji  `json:"emoji"`
+}
+
+func (*MessageReactionRemoveEmoji) EventType() string { return TypeMessageReactionRemoveEmoji }
+
+func reactionsURL(channelID string, messageID string, emoji Emoji) string {
+	return fmt.Sprintf("%s/channels/%s/messages/%s/reactions/%s", apiBase, channelID, messageID, emoji.URLEncoded())
+}
+
+func (s *Session) AddReaction(ctx context.Context, channelID string, messageID string, emoji Emoji, opts ...RequestOption) error {
+	return s.requestJSON(ctx, "PUT", reactionsURL(channelID, messageID, emoji)+"/@me", nil, nil, opts...)
+}
+
+func (s *Session) RemoveOwnReaction(ctx context.Context, channelID string, messageID string, emoji Emoji, opts ...RequestOption) error {
+	return s.requestJSON(ctx, "DELETE", reactionsURL(channelID, messageID, emoji)+"/@me", nil, nil, opts...)
+}
+
+func (s *Session) RemoveUserReaction(ctx context.Context, channelID string, messageID string, emoji Emoji, userID string, opts ...RequestOption) error {
+	return s.requestJSON(ctx, "DELETE", reactionsURL(channelID, messageID, emoji)+"/"+userID, nil, nil, opts...)
+}
+
+// Removes every reaction on a message
+func (s *Session) RemoveAllReactions(ctx context.Context, channelID string, messageID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/messages/%s/reactions", apiBase, channelID, messageID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+// Removes every reaction for a single emoji on a message
+func (s *Session) RemoveAllReactionsForEmoji(ctx context.Context, channelID string, messageID string, emoji Emoji, opts ...RequestOption) error {
+	return s.requestJSON(ctx, "DELETE", reactionsURL(channelID, messageID, emoji), nil, nil, opts...)
+}
+
+// Returns an iterator over the users that reacted with an emoji, fetched 100 at a time.
+// reactionType is ReactionTypeNormal or ReactionTypeBurst.
+func (s *Session) ReactionUsers(ctx context.Context, channelID string, messageID string, emoji Emoji, reactionType int, opts ...RequestOption) iter.Seq2[User, error] {
+	return func(yield func(User, error) bool) {
+		after := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(User{}, err)
+				return
+			}
+
+			params := url.Values{}
+			params.Set("limit", "100")
+			params.Set("type", strconv.Itoa(reactionType))
+			if after != "" {
+				params.Set("after", after)
+			}
+			var page []User
+			if err := s.requestJSON(ctx, "GET", reactionsURL(channelID, messageID, emoji)+"?"+params.Encode(), nil, &page, opts...); err != nil {
+				yield(User{}, err)
+				return
+			}
+			for _, user := range page {
+				if !yield(user, nil) {
+					return
+				}
+			}
+			if len(page) < 100 {
+				return
+			}
+			after = page[len(page)-1].ID
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Handles REST requests
+package discordgowrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	maxRateLimitRetries = 3 // times a request is retried after being rate limited
+	defaultRetries      = 3 // times an idempotent request is retried after a 5xx or network error
+	retryBackoff        = 500 * time.Millisecond
+)
+
+// Options applied to a single REST request
+type RequestOptions struct {
+	AuditLogReason string        // Sent as X-Audit-Log-Reason, shows up in the guild audit log
+	Timeout        time.Duration // Timeout for the whole request including rate limit waits, 0 for none
+	Retries        int           // Retries after a 5xx or network error, only used for idempotent methods
+
+	noAuth bool // Interaction endpoints are authorized by the token in the URL
+}
+
+type RequestOption func(*RequestOptions)
+
+func WithAuditLogReason(reason string) RequestOption {
+	return func(o *RequestOptions) {
+		o.AuditLogReason = reason
+	}
+}
+
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *RequestOptions) {
+		o.Timeout = timeout
+	}
+}
+
+func WithRetries(retries int) RequestOption {
+	return func(o *RequestOptions) {
+		o.Retries = retries
+	}
+}
+
+func withoutAuth() RequestOption {
+	return func(o *RequestOptions) {
+		o.noAuth = true
+	}
+}
+
+func newRequestOptions(opts []RequestOption) RequestOptions {
+	options := RequestOptions{Retries: defaultRetries}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// Returns whether a request can safely be sent again after a failure
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// Returns a new reader for the request body and its content type.
+// It is called again for every retry, so it must be able to replay the body.
+type requestBody func() (io.Reader, string, error)
+
+func jsonBody(body []byte) requestBody {
+	return func() (io.Reader, string, error) {
+		return bytes.NewReader(body), "application/json", nil
+	}
+}
+
+// Marshals data as the JSON body of a request and unmarshals the response into v.
+// Either data or v can be nil.
+func (s *Session) requestJSON(ctx context.Context, method string, url string, data interface{}, v interface{}, opts ...RequestOption) error {
+	var body requestBody
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("error marshaling request: %v", err)
+		}
+		body = jsonBody(payload)
+	}
+	recvBody, err := s.request(ctx, method, url, body, opts...)
+	if err != nil {
+		return err
+	}
+	return unmarshalResponse(recvBody, v)
+}
+
+func unmarshalResponse(recvBody []byte, v interface{}) error {
+	if v == nil || len(recvBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(recvBody, v); err != nil {
+		return fmt.Errorf("error unmarshaling response: %v", err)
+	}
+	return nil
+}
+
+// Sends a request through the rate limiter and returns the response body.
+// Requests to the same bucket are sent one at a time, while requests to
+// unrelated buckets can proceed in parallel.
+func (s *Session) request(ctx context.Context, method string, url string, body requestBody, opts ...RequestOption) ([]byte, error) {
+	options := newRequestOptions(opts)
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	route := routeKey(method, url)
+	major := majorParameter(url)
+
+	b, err := s.rateLimiter.lockBucket(ctx, route, major)
+	if err != nil {
+		return nil, err
+	}
+	defer b.unlock()
+
+	rateLimited, failures := 0, 0
+	for {
+		if err := s.rateLimiter.checkInvalid(); err != nil {
+			return nil, err
+		}
+		if err := s.rateLimiter.waitGlobal(ctx); err != nil {
+			return nil, err
+		}
+
+		status, header, recvBody, err := s.send(ctx, method, url, body, options)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, errBodyNotReplayable) && isIdempotent(method) && failures < options.Retries {
+				failures++
+				log.Printf("[REST] Request to %s failed, retrying: %v\n", route, err)
+				if err := sleepContext(ctx, retryBackoff<<(failures-1)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		s.rateLimiter.update(route, major, b, header)
+		if isInvalidResponse(status, header) {
+			s.rateLimiter.recordInvalid()
+		}
+
+		if status == http.StatusTooManyRequests && rateLimited < maxRateLimitRetries {
+			rateLimited++
+			wait := retryAfter(header, recvBody)
+			if header.Get("X-RateLimit-Global") == "true" {
+				log.Printf("[REST] Hit global rate limit, retrying in %v\n", wait)
+				s.rateLimiter.setGlobalReset(wait)
+			} else {
+				log.Printf("[REST] Rate limited on %s, retrying in %v\n", route, wait)
+			}
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if status >= 500 && isIdempotent(method) && failures < options.Retries {
+			failures++
+			log.Printf("[REST] Request to %s failed with status %d, retrying\n", route, status)
+			if err := sleepContext(ctx, retryBackoff<<(failures-1)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if status < 200 || status >= 300 {
+			return nil, newRESTError(status, recvBody)
+		}
+		return recvBody, nil
+	}
+}
+
+// Sends a single HTTP request and reads the whole response
+func (s *Session) send(ctx context.Context, method string, endpoint string, body requestBody, options RequestOptions) (int, http.Header, []byte, error) {
+	var reader io.Reader
+	var contentType string
+	if body != nil {
+		var err error
+		if reader, contentType, err = body(); err != nil {
+			return 0, nil, nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if !options.noAuth {
+		req.Header.Set("Authorization", "Bot "+s.Token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if options.AuditLogReason != "" {
+		// Reasons must be URL encoded
+		req.Header.Set("X-Audit-Log-Reason", url.PathEscape(options.AuditLogReason))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	recvBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return resp.StatusCode, resp.Header, recvBody, nil
+}
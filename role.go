@@ -0,0 +1,160 @@
+// Handles guild roles
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// https://discord.com/developers/docs/topics/permissions#role-object
+type Role struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Color        int         `json:"color"`
+	Hoist        bool        `json:"hoist"`
+	Icon         string      `json:"icon,omitempty"`
+	UnicodeEmoji string      `json:"unicode_emoji,omitempty"`
+	Position     int         `json:"position"`
+	Permissions  Permissions `json:"permissions"`
+	Managed      bool        `json:"managed"`
+	Mentionable  bool        `json:"mentionable"`
+	Tags         *RoleTags   `json:"tags,omitempty"`
+	Flags        int         `json:"flags"`
+}
+
+// Returns the string that mentions the role in a message
+func (r *Role) Mention() string {
+	return "<@&" + r.ID + ">"
+}
+
+// https://discord.com/developers/docs/topics/permissions#role-object-role-tags-structure
+// Discord sends some tags as null when set and leaves them out otherwise,
+// those are decoded as booleans.
+type RoleTags struct {
+	BotID                 string `json:"bot_id,omitempty"`
+	IntegrationID         string `json:"integration_id,omitempty"`
+	PremiumSubscriber     bool   `json:"-"` // the booster role
+	SubscriptionListingID string `json:"subscription_listing_id,omitempty"`
+	AvailableForPurchase  bool   `json:"-"`
+	GuildConnections      bool   `json:"-"` // the guild's linked role
+}
+
+func (t *RoleTags) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	decode := func(key string, v *string) error {
+		if value, ok := raw[key]; ok {
+			return json.Unmarshal(value, v)
+		}
+		return nil
+	}
+	if err := decode("bot_id", &t.BotID); err != nil {
+		return err
+	}
+	if err := decode("integration_id", &t.IntegrationID); err != nil {
+		return err
+	}
+	if err := decode("subscription_listing_id", &t.SubscriptionListingID); err != nil {
+		return err
+	}
+	_, t.PremiumSubscriber = raw["premium_subscriber"]
+	_, t.AvailableForPurchase = raw["available_for_purchase"]
+	_, t.GuildConnections = raw["guild_connections"]
+	return nil
+}
+
+// https://discord.com/developers/docs/resources/guild#create-guild-role-json-params
+// Nil fields are left unchanged when modifying, or use Discord's defaults when creating.
+type RoleEdit struct {
+	Name         *string      `json:"name,omitempty"`
+	Permissions  *Permissions `json:"permissions,omitempty"`
+	Color        *int         `json:"color,omitempty"`
+	Hoist        *bool        `json:"hoist,omitempty"`
+	Icon         *string      `json:"icon,omitempty"` // image data URI
+	UnicodeEmoji *string      `json:"unicode_emoji,omitempty"`
+	Mentionable  *bool        `json:"mentionable,omitempty"`
+}
+
+// A new position for a role, used with ModifyRolePositions
+type RolePosition struct {
+	ID       string `json:"id"`
+	Position int    `json:"position"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#guild-role-create
+type GuildRoleCreate struct {
+	GuildID string `json:"guild_id"`
+	Role    Role   `json:"role"`
+}
+
+func (*GuildRoleCreate) EventType() string { return TypeGuildRoleCreate }
+
+// https://discord.com/developers/docs/events/gateway-events#guild-role-update
+type GuildRoleUpdate struct {
+	GuildID string `json:"guild_id"`
+	Role    Role   `json:"role"`
+}
+
+func (*GuildRoleUpdate) EventType() string { return TypeGuildRoleUpdate }
+
+// https://discord.com/developers/docs/events/gateway-events#guild-role-delete
+type GuildRoleDelete struct {
+	GuildID string `json:"guild_id"`
+	RoleID  string `json:"role_id"`
+}
+
+func (*GuildRoleDelete) EventType() string { return TypeGuildRoleDelete }
+
+func (s *Session) GuildRoles(ctx context.Context, guildID string, opts ...RequestOption) ([]Role, error) {
+	url := fmt.Sprintf("%s/guilds/%s/roles", apiBase, guildID)
+	var roles []Role
+	if err := s.requestJSON(ctx, "GET", url, nil, &roles, opts...); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (s *Session) GetRole(ctx context.Context, guildID string, roleID string, opts ...RequestOption) (*Role, error) {
+	url := fmt.Sprintf("%s/guilds/%s/roles/%s", apiBase, guildID, roleID)
+	var role Role
+	if err := s.requestJSON(ctx, "GET", url, nil, &role, opts...); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (s *Session) CreateRole(ctx context.Context, guildID string, data RoleEdit, opts ...RequestOption) (*Role, error) {
+	url := fmt.Sprintf("%s/guilds/%s/roles", apiBase, guildID)
+	var role Role
+	if err := s.requestJSON(ctx, "POST", url, data, &role, opts...); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (s *Session) ModifyRole(ctx context.Context, guildID string, roleID string, data RoleEdit, opts ...RequestOption) (*Role, error) {
+	url := fmt.Sprintf("%s/guilds/%s/roles/%s", apiBase, guildID, roleID)
+	var role Role
+	if err := s.requestJSON(ctx, "PATCH", url, data, &role, opts...); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (s *Session) DeleteRole(ctx context.Context, guildID string, roleID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/guilds/%s/roles/%s", apiBase, guildID, roleID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+// Moves roles to new positions and returns all roles of the guild
+func (s *Session) ModifyRolePositions(ctx context.Context, guildID string, positions []RolePosition, opts ...RequestOption) ([]Role, error) {
+	url := fmt.Sprintf("%s/guilds/%s/roles", apiBase, guildID)
+	var roles []Role
+	if err := s.requestJSON(ctx, "PATCH", url, positions, &roles, opts...); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
@@ -0,0 +1,822 @@
+// Handles routing interactions to registered handlers
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type CommandHandler func(c *CommandContext) error
+
+// Wraps a handler, e.g. to check permissions before calling it
+type Middleware func(next CommandHandler) CommandHandler
+
+// Passed to all interaction handlers, it can be used as the context for REST calls
+type InteractionContext struct {
+	context.Context
+	Session     *Session
+	Interaction *Interaction
+	Route       string // what the interaction was routed by, e.g. "/mod ban" or a custom ID prefix
+}
+
+// Responds to the interaction with a message
+func (c *InteractionContext) Respond(data InteractionResponseData) error {
+	return c.Session.Respond(c, c.Interaction, InteractionResponse{
+		Type: InteractionResponseChannelMessageWithSource,
+		Data: &data,
+	})
+}
+
+func (c *InteractionContext) Reply(content string) error {
+	return c.Respond(InteractionResponseData{Content: content})
+}
+
+// Replies with a message only the user who triggered the interaction can see
+func (c *InteractionContext) ReplyEphemeral(content string) error {
+	return c.Respond(InteractionResponseData{Content: content, Flags: MessageFlagEphemeral})
+}
+
+func (c *InteractionContext) DeferReply(ephemeral bool) error {
+	return c.Session.DeferReply(c, c.Interaction, ephemeral)
+}
+
+// Edits the message a component is on as the response to a component interaction
+func (c *InteractionContext) Update(data InteractionResponseData) error {
+	return c.Session.Respond(c, c.Interaction, InteractionResponse{
+		Type: InteractionResponseUpdateMessage,
+		Data: &data,
+	})
+}
+
+func (c *InteractionContext) DeferUpdate() error {
+	return c.Session.DeferUpdate(c, c.Interaction)
+}
+
+// Edits the response, e.g. to send the result after DeferReply
+func (c *InteractionContext) EditResponse(data MessageEdit) (*Message, error) {
+	return c.Session.EditOriginalResponse(c, c.Interaction, data)
+}
+
+func (c *InteractionContext) Followup(data WebhookParams) (*Message, error) {
+	return c.Session.CreateFollowup(c, c.Interaction, data)
+}
+
+// Passed to command handlers, Route is the invoked command including subcommands
+type CommandContext struct {
+	InteractionContext
+	Data    *ApplicationCommandData
+	Options []*InteractionOption // the options of the invoked (sub)command
+}
+
+// Returns the option with the given name, nil if it wasn't given
+func (c *CommandContext) Option(name string) *InteractionOption {
+	for _, o := range c.Options {
+		if o.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+type ComponentHandler func(c *ComponentContext) error
+
+// Passed to component handlers, Route is the custom ID prefix the handler was registered for
+type ComponentContext struct {
+	InteractionContext
+	Data   *MessageComponentData
+	Params []string // the parts of the custom ID after the prefix
+}
+
+// Returns the parameter at index, or an empty string if there are fewer parameters
+func (c *ComponentContext) Param(index int) string {
+	if index < 0 || index >= len(c.Params) {
+		return ""
+	}
+	return c.Params[index]
+}
+
+type commandRoute struct {
+	path        []string
+	description string
+	options     []*CommandOption
+	handler     CommandHandler
+}
+
+// Identifies a top level command, names only have to be unique per command type
+type commandKey struct {
+	commandType int
+	name        string
+}
+
+// Routes commands, component, modal and autocomplete interactions received from the gateway to registered handlers
+type Router struct {
+	session *Session
+
+	mutex        sync.RWMutex
+	commands     map[string]*commandRoute    // by path
+	components   map[string]ComponentHandler // by custom ID prefix
+	modals       map[string]ModalHandler     // by custom ID prefix
+	autocomplete map[autocompleteKey]AutocompleteProvider
+	contextMenus map[commandKey]CommandHandler
+	descriptions map[string]string          // of top level commands and subcommand groups, by path
+	permissions  map[commandKey]Permissions // default member permissions of top level and context menu commands
+	middleware   []Middleware
+
+	// Called when a handler returns an error, logs it by default
+	OnError func(c *InteractionContext, err error)
+
+	// Calls Sync once READY is received, for SyncGuildID or globally if it is empty.
+	// Set it before the first GetMessage call, after the commands are added.
+	SyncOnReady bool
+	SyncGuildID string
+	syncOnce    sync.Once
+}
+
+// Creates a router that handles INTERACTION_CREATE events of the session
+func NewRouter(s *Session) *Router {
+	r := &Router{
+		session:      s,
+		commands:     make(map[string]*commandRoute),
+		components:   make(map[string]ComponentHandler),
+		modals:       make(map[string]ModalHandler),
+		autocomplete: make(map[autocompleteKey]AutocompleteProvider),
+		contextMenus: make(map[commandKey]CommandHandler),
+		descriptions: make(map[string]string),
+		permissions:  make(map[commandKey]Permissions),
+	}
+	r.OnError = func(c *InteractionContext, err error) {
+		log.Printf("[Router] %s failed: %v\n", c.Route, err)
+		// The handler didn't run for these, so the interaction hasn't been responded to
+		var cooldown *CooldownError
+		if errors.Is(err, ErrMissingPermissions) || errors.As(err, &cooldown) {
+			if err := c.ReplyEphemeral(err.Error()); err != nil {
+				log.Printf("[Router] Failed to respond to %s: %v\n", c.Route, err)
+			}
+		}
+	}
+	AddHandler(s, func(s *Session, e *InteractionCreate) {
+		if err := r.HandleInteraction(context.Background(), &e.Interaction); err != nil {
+			log.Printf("[Router] Failed to handle interaction %s: %v\n", e.ID, err)
+		}
+	})
+	AddHandler(s, func(s *Session, e *ReadyCreate) {
+		if !r.SyncOnReady {
+			return
+		}
+		r.syncOnce.Do(func() {
+			go func() {
+				if err := r.Sync(context.Background(), r.SyncGuildID); err != nil {
+					log.Printf("[Router] Failed to sync commands: %v\n", err)
+				}
+			}()
+		})
+	})
+	return r
+}
+
+// Adds middleware that runs before every command handler, in the order added
+func (r *Router) Use(middleware ...Middleware) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Sets the description of a top level command or subcommand group that only has subcommands
+func (r *Router) Describe(path string, description string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.descriptions[joinPath(path)] = description
+}
+
+// Sets the permissions members need to see a top level slash command.
+// Context menu commands use SetUserCommandPermissions and SetMessageCommandPermissions.
+func (r *Router) SetDefaultPermissions(name string, permissions Permissions) {
+	r.setPermissions(CommandTypeChatInput, name, permissions)
+}
+
+func (r *Router) setPermissions(commandType int, name string, permissions Permissions) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.permissions[commandKey{commandType, name}] = permissions
+}
+
+// Registers a handler without options. path is the command name, optionally
+// followed by a subcommand group and subcommand, e.g. "mod ban" or "mod user ban".
+func (r *Router) Handle(path string, description string, handler CommandHandler, middleware ...Middleware) {
+	r.addCommand(path, description, nil, handler, middleware)
+}
+
+// Registers a handler whose options are defined by the tagged fields of T.
+// Fields are filled from the interaction options before the handler is called.
+//
+//	type BanArgs struct {
+//		User   *discordgowrap.User `option:"user" description:"User to ban" required:"true"`
+//		Reason string              `option:"reason" description:"Reason for the ban"`
+//		Days   int                 `option:"days" description:"Days of messages to delete" min:"0" max:"7"`
+//	}
+//
+//	discordgowrap.Command(r, "ban", "Bans a user", func(c *discordgowrap.CommandContext, args *BanArgs) error {
+//		...
+//	})
+//
+// Supported field types are strings, integers, floats and bools (or pointers to them
+// for optional options), *User, *Member, *Channel, *Role and *Attachment.
+// Other tags are min, max, min_length, max_length, choices (comma separated) and channel_types.
+// Panics if T is not a struct or has unsupported fields.
+func Command[T any](r *Router, path string, description string, handler func(c *CommandContext, args *T) error, middleware ...Middleware) {
+	bindings, options, err := parseOptions(reflect.TypeFor[T]())
+	if err != nil {
+		panic(fmt.Sprintf("discordgowrap: command %q: %v", path, err))
+	}
+	r.addCommand(path, description, options, func(c *CommandContext) error {
+		var args T
+		bindOptions(reflect.ValueOf(&args).Elem(), bindings, c)
+		return handler(c, &args)
+	}, middleware)
+}
+
+// Normalizes the spacing of a command path
+func joinPath(path string) string {
+	return strings.Join(strings.Fields(path), " ")
+}
+
+func (r *Router) addCommand(path string, description string, options []*CommandOption, handler CommandHandler, middleware []Middleware) {
+	parts := strings.Fields(path)
+	if len(parts) == 0 || len(parts) > 3 {
+		panic(fmt.Sprintf("discordgowrap: invalid command path %q", path))
+	}
+	// Apply the route's own middleware, router middleware is applied when handling
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.commands[strings.Join(parts, " ")] = &commandRoute{
+		path:        parts,
+		description: description,
+		options:     options,
+		handler:     handler,
+	}
+}
+
+// Registers a handler for components whose custom ID is prefix, or starts with prefix
+// followed by parameters as built by CustomID. The longest matching prefix is used.
+func (r *Router) HandleComponent(prefix string, handler ComponentHandler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.components[prefix] = handler
+}
+
+// Returns the definitions of all registered commands, as sent to Discord by Sync
+func (r *Router) Commands() []*ApplicationCommand {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	byName := make(map[string]*ApplicationCommand)
+	var commands []*ApplicationCommand
+	top := func(name string) *ApplicationCommand {
+		if cmd, exists := byName[name]; exists {
+			return cmd
+		}
+		cmd := &ApplicationCommand{
+			Type:        CommandTypeChatInput,
+			Name:        name,
+			Description: r.describe(name),
+		}
+		if perms, exists := r.permissions[commandKey{CommandTypeChatInput, name}]; exists {
+			cmd.DefaultMemberPermissions = &perms
+		}
+		byName[name] = cmd
+		commands = append(commands, cmd)
+		return cmd
+	}
+
+	paths := make([]string, 0, len(r.commands))
+	for path := range r.commands {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		route := r.commands[path]
+		options := r.autocompleteOptions(path, route.options)
+		cmd := top(route.path[0])
+		switch len(route.path) {
+		case 1:
+			cmd.Description = route.description
+			cmd.Options = options
+		case 2:
+			cmd.Options = append(cmd.Options, &CommandOption{
+				Type:        OptionTypeSubCommand,
+				Name:        route.path[1],
+				Description: route.description,
+				Options:     options,
+			})
+		case 3:
+			var group *CommandOption
+			for _, o := range cmd.Options {
+				if o.Type == OptionTypeSubCommandGroup && o.Name == route.path[1] {
+					group = o
+				}
+			}
+			if group == nil {
+				group = &CommandOption{
+					Type:        OptionTypeSubCommandGroup,
+					Name:        route.path[1],
+					Description: r.describe(route.path[0] + " " + route.path[1]),
+				}
+				cmd.Options = append(cmd.Options, group)
+			}
+			group.Options = append(group.Options, &CommandOption{
+				Type:        OptionTypeSubCommand,
+				Name:        route.path[2],
+				Description: route.description,
+				Options:     options,
+			})
+		}
+	}
+	return append(commands, r.contextMenuCommands()...)
+}
+
+func (r *Router) describe(path string) string {
+	if description, exists := r.descriptions[path]; exists {
+		return description
+	}
+	return path
+}
+
+// Registers the commands with Discord, for a guild or globally if guildID is empty.
+// Nothing is sent if Discord already has the same definitions, so it is safe to call on every startup.
+func (r *Router) Sync(ctx context.Context, guildID string) error {
+	commands := r.Commands()
+
+	existing, err := r.session.listCommands(ctx, guildID, nil)
+	if err != nil {
+		return err
+	}
+	if sameCommands(existing, commands) {
+		return nil
+	}
+
+	log.Printf("[Router] Syncing %d commands\n", len(commands))
+	if guildID == "" {
+		_, err = r.session.BulkOverwriteGlobalCommands(ctx, commands)
+	} else {
+		_, err = r.session.BulkOverwriteGuildCommands(ctx, guildID, commands)
+	}
+	return err
+}
+
+// Compares the fields of commands that are set by the router
+func sameCommands(a []*ApplicationCommand, b []*ApplicationCommand) bool {
+	normalize := func(commands []*ApplicationCommand) string {
+		type comparable struct {
+			Type                     int              `json:"type"`
+			Name                     string           `json:"name"`
+			Description              string           `json:"description"`
+			Options                  []*CommandOption `json:"options"`
+			DefaultMemberPermissions *Permissions     `json:"default_member_permissions"`
+			NSFW                     bool             `json:"nsfw"`
+		}
+		list := make([]comparable, 0, len(commands))
+		for _, c := range commands {
+			commandType := c.Type
+			if commandType == 0 {
+				commandType = CommandTypeChatInput
+			}
+			list = append(list, comparable{commandType, c.Name, c.Description, c.Options, c.DefaultMemberPermissions, c.NSFW})
+		}
+		slices.SortFunc(list, func(x, y comparable) int {
+			return strings.Compare(strconv.Itoa(x.Type)+x.Name, strconv.Itoa(y.Type)+y.Name)
+		})
+		data, _ := json.Marshal(list)
+		return string(data)
+	}
+	return normalize(a) == normalize(b)
+}
+
+// Routes an interaction to its handler. Used for gateway events by NewRouter,
+// and can be called directly for interactions received in other ways.
+func (r *Router) HandleInteraction(ctx context.Context, i *Interaction) error {
+	switch i.Type {
+	case InteractionTypeApplicationCommand:
+		return r.handleCommand(ctx, i)
+	case InteractionTypeMessageComponent:
+		return r.handleComponent(ctx, i)
+	case InteractionTypeModalSubmit:
+		return r.handleModal(ctx, i)
+	case InteractionTypeAutocomplete:
+		return r.handleAutocomplete(ctx, i)
+	}
+	return nil
+}
+
+func (r *Router) handleError(c *InteractionContext, err error) {
+	if err != nil && r.OnError != nil {
+		r.OnError(c, err)
+	}
+}
+
+// Walks down subcommand groups and subcommands to the invoked command,
+// returns its path and options
+func invokedCommand(data *ApplicationCommandData) (string, []*InteractionOption) {
+	path := []string{data.Name}
+	options := data.Options
+	for len(options) == 1 && (options[0].Type == OptionTypeSubCommandGroup || options[0].Type == OptionTypeSubCommand) {
+		path = append(path, options[0].Name)
+		options = options[0].Options
+	}
+	return strings.Join(path, " "), options
+}
+
+func (r *Router) handleCommand(ctx context.Context, i *Interaction) error {
+	data, err := i.CommandData()
+	if err != nil {
+		return err
+	}
+
+	var handler CommandHandler
+	var route string
+	var options []*InteractionOption
+	r.mutex.RLock()
+	switch data.Type {
+	case CommandTypeUser, CommandTypeMessage:
+		handler = r.contextMenus[commandKey{data.Type, data.Name}]
+		route = data.Name
+	default:
+		var path string
+		path, options = invokedCommand(data)
+		if cmd, exists := r.commands[path]; exists {
+			handler = cmd.handler
+		}
+		route = "/" + path
+	}
+	middleware := r.middleware
+	r.mutex.RUnlock()
+	if handler == nil {
+		return fmt.Errorf("no handler for command %s", route)
+	}
+
+	c := &CommandContext{
+		InteractionContext: InteractionContext{
+			Context:     ctx,
+			Session:     r.session,
+			Interaction: i,
+			Route:       route,
+		},
+		Data:    data,
+		Options: options,
+	}
+	for j := len(middleware) - 1; j >= 0; j-- {
+		handler = middleware[j](handler)
+	}
+	r.handleError(&c.InteractionContext, handler(c))
+	return nil
+}
+
+func (r *Router) handleComponent(ctx context.Context, i *Interaction) error {
+	data, err := i.ComponentData()
+	if err != nil {
+		return err
+	}
+
+	handler, prefix, params, exists := matchCustomID(r, r.components, data.CustomID)
+	if !exists {
+		return fmt.Errorf("no handler for component %q", data.CustomID)
+	}
+
+	c := &ComponentContext{
+		InteractionContext: InteractionContext{
+			Context:     ctx,
+			Session:     r.session,
+			Interaction: i,
+			Route:       prefix,
+		},
+		Data:   data,
+		Params: params,
+	}
+	r.handleError(&c.InteractionContext, handler(c))
+	return nil
+}
+
+// Finds the handler registered for the longest prefix of a custom ID
+func matchCustomID[H any](r *Router, handlers map[string]H, customID string) (handler H, prefix string, params []string, exists bool) {
+	parts := strings.Split(customID, customIDSeparator)
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for n := len(parts); n > 0; n-- {
+		prefix = strings.Join(parts[:n], customIDSeparator)
+		if handler, exists = handlers[prefix]; exists {
+			return handler, prefix, parts[n:], true
+		}
+	}
+	return handler, "", nil, false
+}
+
+// How a struct field is filled from an option
+type optionBinding struct {
+	index      int
+	name       string
+	optionType int
+}
+
+var (
+	userType       = reflect.TypeFor[*User]()
+	memberType     = reflect.TypeFor[*Member]()
+	channelType    = reflect.TypeFor[*Channel]()
+	roleType       = reflect.TypeFor[*Role]()
+	attachmentType = reflect.TypeFor[*Attachment]()
+)
+
+// Returns the option type for a field type
+func fieldOptionType(t reflect.Type) (int, error) {
+	switch t {
+	case userType, memberType:
+		return OptionTypeUser, nil
+	case channelType:
+		return OptionTypeChannel, nil
+	case roleType:
+		return OptionTypeRole, nil
+	case attachmentType:
+		return OptionTypeAttachment, nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return OptionTypeString, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return OptionTypeInteger, nil
+	case reflect.Float32, reflect.Float64:
+		return OptionTypeNumber, nil
+	case reflect.Bool:
+		return OptionTypeBoolean, nil
+	}
+	return 0, fmt.Errorf("unsupported option type %s", t)
+}
+
+// Builds the option definitions and bindings from the tagged fields of a struct
+func parseOptions(t reflect.Type) ([]optionBinding, []*CommandOption, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("arguments must be a struct, got %s", t)
+	}
+
+	var bindings []optionBinding
+	var options []*CommandOption
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup("option")
+		if !ok {
+			continue
+		}
+		optionType, err := fieldOptionType(field.Type)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+
+		option := &CommandOption{
+			Type:         optionType,
+			Name:         name,
+			Description:  field.Tag.Get("description"),
+			Required:     field.Tag.Get("required") == "true",
+			Autocomplete: field.Tag.Get("autocomplete") == "true",
+		}
+		if option.Description == "" {
+			option.Description = name
+		}
+		if err := parseOptionLimits(option, field.Tag); err != nil {
+			return nil, nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+
+		// Discord requires required options to come first
+		if option.Required {
+			insert := 0
+			for insert < len(options) && options[insert].Required {
+				insert++
+			}
+			options = slices.Insert(options, insert, option)
+		} else {
+			options = append(options, option)
+		}
+		bindings = append(bindings, optionBinding{index: i, name: name, optionType: optionType})
+	}
+	return bindings, options, nil
+}
+
+func parseOptionLimits(option *CommandOption, tag reflect.StructTag) error {
+	parseFloat := func(key string) (*float64, error) {
+		value, ok := tag.Lookup(key)
+		if !ok {
+			return nil, nil
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+		return &f, nil
+	}
+	parseInt := func(key string) (*int, error) {
+		value, ok := tag.Lookup(key)
+		if !ok {
+			return nil, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+		return &n, nil
+	}
+
+	var err error
+	if option.MinValue, err = parseFloat("min"); err != nil {
+		return err
+	}
+	if option.MaxValue, err = parseFloat("max"); err != nil {
+		return err
+	}
+	if option.MinLength, err = parseInt("min_length"); err != nil {
+		return err
+	}
+	if option.MaxLength, err = parseInt("max_length"); err != nil {
+		return err
+	}
+
+	if choices, ok := tag.Lookup("choices"); ok {
+		for _, choice := range strings.Split(choices, ",") {
+			choice = strings.TrimSpace(choice)
+			var value interface{} = choice
+			switch option.Type {
+			case OptionTypeInteger:
+				n, err := strconv.ParseInt(choice, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid choice %q: %v", choice, err)
+				}
+				value = n
+			case OptionTypeNumber:
+				f, err := strconv.ParseFloat(choice, 64)
+				if err != nil {
+					return fmt.Errorf("invalid choice %q: %v", choice, err)
+				}
+				value = f
+			}
+			option.Choices = append(option.Choices, &CommandChoice{Name: choice, Value: value})
+		}
+	}
+
+	if channelTypes, ok := tag.Lookup("channel_types"); ok {
+		for _, ct := range strings.Split(channelTypes, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(ct))
+			if err != nil {
+				return fmt.Errorf("invalid channel type %q: %v", ct, err)
+			}
+			option.ChannelTypes = append(option.ChannelTypes, n)
+		}
+	}
+	return nil
+}
+
+// Fills the fields of args from the options of the interaction
+func bindOptions(args reflect.Value, bindings []optionBinding, c *CommandContext) {
+	resolved := c.Data.Resolved
+	if resolved == nil {
+		resolved = &ResolvedData{}
+	}
+
+	for _, b := range bindings {
+		option := c.Option(b.name)
+		if option == nil {
+			continue
+		}
+		field := args.Field(b.index)
+		id := option.StringValue()
+
+		switch field.Type() {
+		case userType:
+			if user, exists := resolved.Users[id]; exists {
+				field.Set(reflect.ValueOf(&user))
+			}
+			continue
+		case memberType:
+			if member, exists := resolved.Members[id]; exists {
+				if user, exists := resolved.Users[id]; exists {
+					member.User = &user
+				}
+				member.GuildID = c.Interaction.GuildID
+				field.Set(reflect.ValueOf(&member))
+			}
+			continue
+		case channelType:
+			if channel, exists := resolved.Channels[id]; exists {
+				field.Set(reflect.ValueOf(&channel))
+			}
+			continue
+		case roleType:
+			if role, exists := resolved.Roles[id]; exists {
+				field.Set(reflect.ValueOf(&role))
+			}
+			continue
+		case attachmentType:
+			if attachment, exists := resolved.Attachments[id]; exists {
+				field.Set(reflect.ValueOf(&attachment))
+			}
+			continue
+		}
+
+		if field.Kind() == reflect.Pointer {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(option.StringValue())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(option.IntValue())
+		case reflect.Float32, reflect.Float64:
+			field.SetFloat(option.FloatValue())
+		case reflect.Bool:
+			field.SetBool(option.BoolValue())
+		}
+	}
+}
+
+var ErrMissingPermissions = errors.New("you don't have permission to use this command")
+
+// Middleware that only runs the handler if the member has all the given permissions
+func RequirePermissions(permissions Permissions) Middleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(c *CommandContext) error {
+			member := c.Interaction.Member
+			if member == nil || !member.Permissions.Has(permissions) {
+				return ErrMissingPermissions
+			}
+			return next(c)
+		}
+	}
+}
+
+// Returned by the Cooldown middleware when a user runs a command too often
+type CooldownError struct {
+	Remaining time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("command is on cooldown, try again in %s", e.Remaining.Round(time.Second))
+}
+
+// Middleware that lets every user run a command at most once per duration
+func Cooldown(duration time.Duration) Middleware {
+	var mutex sync.Mutex
+	lastUsed := make(map[string]time.Time)
+	return func(next CommandHandler) CommandHandler {
+		return func(c *CommandContext) error {
+			user := c.Interaction.Author()
+			if user == nil {
+				return next(c)
+			}
+			key := c.Route + ":" + user.ID
+
+			mutex.Lock()
+			now := time.Now()
+			if last, exists := lastUsed[key]; exists && now.Sub(last) < duration {
+				mutex.Unlock()
+				return &CooldownError{Remaining: duration - now.Sub(last)}
+			}
+			lastUsed[key] = now
+			// Forget expired entries so the map doesn't grow forever
+			for k, t := range lastUsed {
+				if now.Sub(t) >= duration {
+					delete(lastUsed, k)
+				}
+			}
+			mutex.Unlock()
+			return next(c)
+		}
+	}
+}
+
+// Middleware that logs every command with the user and how long it took
+func LogCommands() Middleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(c *CommandContext) error {
+			start := time.Now()
+			err := next(c)
+			userID := ""
+			if user := c.Interaction.Author(); user != nil {
+				userID = user.ID
+			}
+			log.Printf("[Router] %s by %s in guild %s took %v (error: %v)\n", c.Route, userID, c.Interaction.GuildID, time.Since(start), err)
+			return err
+		}
+	}
+}
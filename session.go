@@ -2,10 +2,9 @@
 package discordgowrap
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 
@@ -17,8 +16,14 @@ type Session struct {
 	conn             *websocket.Conn
 	intents          int
 	Bot              bot
+	ApplicationID    string
 	httpClient       *http.Client
+	rateLimiter      *rateLimiter
 	voiceConnections map[string]*voiceConnection
+	eventHandlers    eventHandlers
+	ready            *ReadyCreate // received while connecting, before handlers can be added
+	readyDispatched  bool         // whether ready was passed to the handlers by GetMessage
+	state            *State       // attached by NewState, used for lookups when set
 }
 
 type SpeakingPayload struct {
@@ -133,13 +138,36 @@ const (
 )
 
 type MessageCreate struct {
+	ID      string `json:"id"`
 	Content string `json:"content"`
 	Author  struct {
 		ID   string `json:"id"`
 		Name string `json:"username"`
+		Bot  bool   `json:"bot"`
 	} `json:"author"`
-	ChannelID string `json:"channel_id"`
-	GuildID   string `json:"guild_id"`
+	ChannelID    string   `json:"channel_id"`
+	GuildID      string   `json:"guild_id"`
+	Member       *Member  `json:"member,omitempty"` // the author in guilds, without User
+	Mentions     []User   `json:"mentions"`
+	MentionRoles []string `json:"mention_roles"`
+
+	message Message
+}
+
+func (*MessageCreate) EventType() string { return TypeMessageCreate }
+
+func (m *MessageCreate) UnmarshalJSON(data []byte) error {
+	// The alias doesn't have this method, so it is decoded normally
+	type messageCreate MessageCreate
+	if err := json.Unmarshal(data, (*messageCreate)(m)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &m.message)
+}
+
+// Returns the full message, including the embeds, attachments and other fields that MessageCreate leaves out
+func (m *MessageCreate) Message() Message {
+	return m.message
 }
 
 type bot struct {
@@ -147,13 +175,19 @@ type bot struct {
 	Name string `json:"username"`
 }
 
+// https://discord.com/developers/docs/events/gateway-events#ready
 type ReadyCreate struct {
-	User struct {
-		ID   string `json:"id"`
-		Name string `json:"username"`
-	} `json:"user"`
+	User        User               `json:"user"`
+	Guilds      []UnavailableGuild `json:"guilds"` // sent as GUILD_CREATE events after READY
+	SessionID   string             `json:"session_id"`
+	Application struct {
+		ID    string `json:"id"`
+		Flags int    `json:"flags"`
+	} `json:"application"`
 }
 
+func (*ReadyCreate) EventType() string { return TypeReady }
+
 type GatewayPayload struct {
 	Op   int         `json:"op"`
 	Data interface{} `json:"d"`
@@ -162,9 +196,22 @@ type GatewayPayload struct {
 }
 
 type Identify struct {
-	Token      string             `json:"token"`
-	Intents    int                `json:"intents"`
-	Properties IdentifyProperties `json:"properties"`
+	Token          string             `json:"token"`
+	Intents        int                `json:"intents"`
+	Properties     IdentifyProperties `json:"properties"`
+	LargeThreshold int                `json:"large_threshold,omitempty"`
+}
+
+// Changes the IDENTIFY payload sent by New
+type IdentifyOption func(*Identify)
+
+// Sets the member count (50-250, 50 by default) above which guilds are large.
+// GUILD_CREATE of a large guild only contains some of its members, so raising
+// it lets MemberCacheSmallGuilds cache members of more guilds.
+func WithLargeThreshold(threshold int) IdentifyOption {
+	return func(i *Identify) {
+		i.LargeThreshold = threshold
+	}
 }
 
 type IdentifyProperties struct {
@@ -179,20 +226,69 @@ type VoiceServerUpdate struct {
 	Token    string `json:"token"`
 }
 
+// https://discord.com/developers/docs/resources/voice#voice-state-object
+type VoiceState struct {
+	GuildID    string  `json:"guild_id,omitempty"`
+	ChannelID  string  `json:"channel_id"` // empty when the user left
+	UserID     string  `json:"user_id"`
+	Member     *Member `json:"member,omitempty"`
+	SessionID  string  `json:"session_id"`
+	Deaf       bool    `json:"deaf"`
+	Mute       bool    `json:"mute"`
+	SelfDeaf   bool    `json:"self_deaf"`
+	SelfMute   bool    `json:"self_mute"`
+	SelfStream bool    `json:"self_stream,omitempty"`
+	SelfVideo  bool    `json:"self_video"`
+	Suppress   bool    `json:"suppress"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#voice-state-update
 type VoiceStateUpdate struct {
-	GuildId   string `json:"guild_id"`
-	ChannelId string `json:"channel_id"`
-	SessionId string `json:"session_id"`
-	Uid       string `json:"user_id"`
+	GuildId    string  `json:"guild_id"`
+	ChannelId  string  `json:"channel_id"`
+	SessionId  string  `json:"session_id"`
+	Uid        string  `json:"user_id"`
+	Member     *Member `json:"member,omitempty"`
+	Deaf       bool    `json:"deaf"`
+	Mute       bool    `json:"mute"`
+	SelfDeaf   bool    `json:"self_deaf"`
+	SelfMute   bool    `json:"self_mute"`
+	SelfStream bool    `json:"self_stream,omitempty"`
+	SelfVideo  bool    `json:"self_video"`
+	Suppress   bool    `json:"suppress"`
 }
 
-type sendMessage struct {
-	Content string `json:"content"`
+func (*VoiceStateUpdate) EventType() string { return TypeVoiceStateUpdate }
+
+// Returns the voice state sent with the update
+func (e *VoiceStateUpdate) VoiceState() VoiceState {
+	return VoiceState{
+		GuildID:    e.GuildId,
+		ChannelID:  e.ChannelId,
+		UserID:     e.Uid,
+		Member:     e.Member,
+		SessionID:  e.SessionId,
+		Deaf:       e.Deaf,
+		Mute:       e.Mute,
+		SelfDeaf:   e.SelfDeaf,
+		SelfMute:   e.SelfMute,
+		SelfStream: e.SelfStream,
+		SelfVideo:  e.SelfVideo,
+		Suppress:   e.Suppress,
+	}
 }
 
 func (s *Session) GetMessage() (string, MessageCreate, error) {
 	var msg MessageCreate
 	var payload GatewayPayload
+	if s.conn == nil {
+		return "", msg, ErrNoGateway
+	}
+	// Handlers added after New get the READY received while connecting
+	if s.ready != nil && !s.readyDispatched {
+		s.readyDispatched = true
+		s.dispatch(s.ready)
+	}
 	if err := s.conn.ReadJSON(&payload); err != nil {
 		return "", msg, err
 	}
@@ -203,24 +299,63 @@ func (s *Session) GetMessage() (string, MessageCreate, error) {
 	}
 	switch payload.Type {
 	case TypeMessageCreate:
-		data, _ := json.Marshal(payload.Data)
-		if err := json.Unmarshal(data, &msg); err != nil {
+		e, err := handleEvent[MessageCreate](s, payload.Data)
+		if err != nil {
 			return payload.Type, msg, err
 		}
-		return payload.Type, msg, nil
+		return payload.Type, *e, nil
+	case TypeReady:
+		_, err := handleEvent[ReadyCreate](s, payload.Data)
+		return payload.Type, msg, err
 	case TypeMessageUpdate:
-		return payload.Type, msg, nil
+		_, err := handleEvent[MessageUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeMessageDelete:
+		_, err := handleEvent[MessageDelete](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeMessageDeleteBulk:
+		_, err := handleEvent[MessageDeleteBulk](s, payload.Data)
+		return payload.Type, msg, err
 	case TypeGuildCreate:
-		return payload.Type, msg, nil
+		_, err := handleEvent[GuildCreate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildUpdate:
+		_, err := handleEvent[GuildUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildDelete:
+		_, err := handleEvent[GuildDelete](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildEmojisUpdate:
+		_, err := handleEvent[GuildEmojisUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildMemberAdd:
+		_, err := handleEvent[GuildMemberAdd](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildMemberUpdate:
+		_, err := handleEvent[GuildMemberUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildMemberRemove:
+		_, err := handleEvent[GuildMemberRemove](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildMembersChunk:
+		_, err := handleEvent[GuildMembersChunk](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeChannelCreate:
+		_, err := handleEvent[ChannelCreate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeChannelUpdate:
+		_, err := handleEvent[ChannelUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeChannelDelete:
+		_, err := handleEvent[ChannelDelete](s, payload.Data)
+		return payload.Type, msg, err
 	case TypeVoiceStateUpdate:
-		fmt.Printf("Voice state update: %v\n", payload.Data)
-		var vsu VoiceStateUpdate
-		data, _ := json.Marshal(payload.Data)
-		if err := json.Unmarshal(data, &vsu); err != nil {
+		vsu, err := handleEvent[VoiceStateUpdate](s, payload.Data)
+		if err != nil {
 			return payload.Type, msg, err
 		}
+		// Other users' voice states only go to the handlers
 		if vsu.Uid != s.Bot.ID {
-			fmt.Printf("Ignoring voice state update for user %s\n", vsu.Uid)
 			return payload.Type, msg, nil
 		}
 		if vsu.ChannelId == "" {
@@ -246,90 +381,95 @@ func (s *Session) GetMessage() (string, MessageCreate, error) {
 		vc.establishVoiceSocketConnection()
 
 		return payload.Type, msg, nil
+	case TypeGuildRoleCreate:
+		_, err := handleEvent[GuildRoleCreate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildRoleUpdate:
+		_, err := handleEvent[GuildRoleUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeGuildRoleDelete:
+		_, err := handleEvent[GuildRoleDelete](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeThreadCreate:
+		_, err := handleEvent[ThreadCreate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeThreadUpdate:
+		_, err := handleEvent[ThreadUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeThreadDelete:
+		_, err := handleEvent[ThreadDelete](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeThreadListSync:
+		_, err := handleEvent[ThreadListSync](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeThreadMemberUpdate:
+		_, err := handleEvent[ThreadMemberUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeThreadMembersUpdate:
+		_, err := handleEvent[ThreadMembersUpdate](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeMessageReactionAdd:
+		_, err := handleEvent[MessageReactionAdd](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeMessageReactionRemove:
+		_, err := handleEvent[MessageReactionRemove](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeMessageReactionRemoveAll:
+		_, err := handleEvent[MessageReactionRemoveAll](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeMessageReactionRemoveEmoji:
+		_, err := handleEvent[MessageReactionRemoveEmoji](s, payload.Data)
+		return payload.Type, msg, err
+	case TypeInteractionCreate:
+		_, err := handleEvent[InteractionCreate](s, payload.Data)
+		return payload.Type, msg, err
 	}
 	log.Printf("Unhandled message type: %s with data: %v\n", payload.Type, payload.Data)
 	return payload.Type, msg, nil
 }
 
-func (s *Session) SendMessage(channelID string, content string) error {
-	fmt.Printf("Sending \"%s\" to channel %s\n", content, channelID)
-	url := fmt.Sprintf("%s/channels/%s/messages", apiBase, channelID)
-	msg := sendMessage{Content: content}
-	body, err := json.Marshal(msg)
-	if err != nil {
-		return fmt.Errorf("error marshaling message: %v", err)
-	}
-	return s.httpRequestNoResponse("POST", url, body)
-}
-
-func (s *Session) httpRequestAndResponse(method string, url string, body []byte) (string, error) {
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Authorization", "Bot "+s.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	recvBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	return string(recvBody), nil
-}
-
-func (s *Session) httpRequestNoResponse(method string, url string, body []byte) error {
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "Bot "+s.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return err
+func (s *Session) findUserChannelIdInGuild(ctx context.Context, guildId string, userId string) string {
+	// Without the intent the state never receives voice states
+	if s.state != nil && s.intents&IntentGuildVoiceStates != 0 {
+		if channelId, known := s.state.voiceChannel(guildId, userId); known {
+			return channelId
+		}
 	}
-	defer resp.Body.Close()
-	return nil
-}
 
-func (s *Session) findUserChannelIdInGuild(guildId string, userId string) string {
 	type voiceState struct {
 		ChannelID string `json:"channel_id"`
 	}
 
 	url := fmt.Sprintf("%s/guilds/%s/voice-states/%s", apiBase, guildId, userId)
 
-	respBody, err := s.httpRequestAndResponse("GET", url, nil)
-	if err != nil {
-		log.Printf("findUserChannelIdInGuild: request error: %v\n", err)
-		return ""
-	}
-	//fmt.Println("[S] Find user response: ", respBody)
-
 	var vs voiceState
-	if err := json.Unmarshal([]byte(respBody), &vs); err != nil {
-		log.Printf("findUserChannelIdInGuild: unmarshal error: %v\nBody: %s\n", err, respBody)
+	if err := s.requestJSON(ctx, "GET", url, nil, &vs); err != nil {
+		log.Printf("findUserChannelIdInGuild: request error: %v\n", err)
 		return ""
 	}
 
 	return vs.ChannelID
 }
 
-func (s *Session) ConnectToVoice(guildId string, userId string) {
+// Joins the voice channel the user is in. Returns a *PermissionError if the bot
+// can't see or connect to the channel.
+func (s *Session) ConnectToVoice(ctx context.Context, guildId string, userId string) error {
+	if s.conn == nil {
+		return ErrNoGateway
+	}
 	// https://discord.com/developers/docs/topics/voice-connections#retrieving-voice-server-information
-	channelId := s.findUserChannelIdInGuild(guildId, userId)
+	channelId := s.findUserChannelIdInGuild(ctx, guildId, userId)
 
 	if channelId == "" {
-		log.Printf("Failed to find channelID\n")
-		return
+		return fmt.Errorf("user %s is not in a voice channel in guild %s", userId, guildId)
+	}
+
+	perms, err := s.ChannelPermissions(ctx, channelId, s.Bot.ID)
+	if err != nil {
+		return fmt.Errorf("error getting permissions for voice channel %s: %v", channelId, err)
+	}
+	if required := PermissionViewChannel | PermissionConnect; !perms.Has(required) {
+		return &PermissionError{ChannelID: channelId, Missing: required &^ perms}
 	}
 
 	payload := GatewayPayload{
@@ -343,10 +483,11 @@ func (s *Session) ConnectToVoice(guildId string, userId string) {
 	}
 
 	if err := s.conn.WriteJSON(payload); err != nil {
-		log.Printf("[S] Error sending VOICE_STATE_UPDATE: %v\n", err)
+		return fmt.Errorf("error sending VOICE_STATE_UPDATE: %v", err)
 	}
-
+	return nil
 }
+
 func (s *Session) Exit() error {
 	// Keep this for now if more things are needed to close.
 	return s.disconnect()
@@ -392,6 +533,10 @@ func (s *Session) DisconnectFromVoice(guildId string) {
 		Data: voiceChannelPost{&guildId, nil, true, false},
 	}
 
+	if s.conn == nil {
+		log.Printf("[VC] Error sending DISCONNECT for voice channel: %v\n", ErrNoGateway)
+		return
+	}
 	err := s.conn.WriteJSON(disc)
 	if err != nil {
 		log.Printf("[VC] Error sending DISCONNECT for voice channel: %v\n", err)
@@ -402,6 +547,9 @@ func (s *Session) DisconnectFromVoice(guildId string) {
 }
 
 func (s *Session) disconnect() error {
+	if s.conn == nil {
+		return nil
+	}
 	// Closes the connection server side
 	disc := GatewayPayload{
 		Op:   OpClose,
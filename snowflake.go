@@ -0,0 +1,24 @@
+// Handles snowflake IDs
+package discordgowrap
+
+import (
+	"strconv"
+	"time"
+)
+
+// https://discord.com/developers/docs/reference#snowflakes
+const discordEpoch = 1420070400000 // milliseconds since the unix epoch
+
+// Returns the time the snowflake was created
+func SnowflakeTime(id string) (time.Time, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(n>>22) + discordEpoch), nil
+}
+
+// Returns the smallest snowflake created at t, useful for paginating by time
+func SnowflakeFromTime(t time.Time) string {
+	return strconv.FormatUint(uint64(t.UnixMilli()-discordEpoch)<<22, 10)
+}
@@ -0,0 +1,738 @@
+// Handles caching guilds, channels, roles, members and users from gateway events
+package discordgowrap
+
+import (
+	"slices"
+	"sync"
+)
+
+// Which members the state keeps, the member of the bot itself is always kept
+type MemberCachePolicy int
+
+const (
+	MemberCacheNone MemberCachePolicy = iota
+	// Only caches members of guilds that aren't large. GUILD_CREATE of a large guild
+	// (more members than the large threshold set with WithLargeThreshold, 50 by default)
+	// only contains members in voice channels and the bot itself, so the cache would be incomplete.
+	MemberCacheSmallGuilds
+	// Caches every member seen in events, requires IntentGuildMembers to stay up to date
+	MemberCacheAll
+)
+
+// Selects what a State keeps, guilds are always kept
+type StateConfig struct {
+	Channels    bool
+	Threads     bool
+	Roles       bool
+	Emojis      bool
+	VoiceStates bool // requires IntentGuildVoiceStates
+	Members     MemberCachePolicy
+	MaxMessages int // messages kept per channel, 0 disables the message cache
+}
+
+// Caches everything except messages, and only members of small guilds
+func DefaultStateConfig() StateConfig {
+	return StateConfig{
+		Channels:    true,
+		Threads:     true,
+		Roles:       true,
+		Emojis:      true,
+		VoiceStates: true,
+		Members:     MemberCacheSmallGuilds,
+	}
+}
+
+type guildState struct {
+	guild       Guild // without Roles and Emojis, they are kept in the maps below
+	large       bool
+	memberCount int
+	unavailable bool
+	channelIDs  map[string]struct{}
+	roles       map[string]Role
+	emojis      map[string]Emoji
+	members     map[string]Member     // by user ID, without User
+	voiceStates map[string]VoiceState // by user ID, only users in a voice channel
+}
+
+// An in-memory cache built from gateway events, so lookups don't need REST calls.
+// Getters return copies, but slices and pointers in them other than Member.User
+// are shared and must not be modified.
+type State struct {
+	config StateConfig
+
+	mutex    sync.RWMutex
+	self     User
+	guilds   map[string]*guildState
+	channels map[string]Channel   // guild channels, threads and DMs by ID
+	users    map[string]User      // by ID
+	messages map[string][]Message // by channel ID, oldest first
+}
+
+// Creates a state that is kept up to date with the events of the session.
+// The events are only received with the matching intents, e.g. IntentGuilds.
+// The state is attached to the session, which then uses it instead of REST
+// calls where it can, e.g. in ChannelPermissions. Call it before reading events.
+func NewState(s *Session, config StateConfig) *State {
+	st := &State{
+		config:   config,
+		guilds:   make(map[string]*guildState),
+		channels: make(map[string]Channel),
+		users:    make(map[string]User),
+		messages: make(map[string][]Message),
+	}
+	// READY was received while connecting, before this handler existed
+	if s.ready != nil {
+		st.onReady(s.ready)
+	}
+	s.state = st
+
+	AddHandler(s, func(s *Session, e *ReadyCreate) { st.onReady(e) })
+	AddHandler(s, func(s *Session, e *GuildCreate) { st.onGuildCreate(e) })
+	AddHandler(s, func(s *Session, e *GuildUpdate) { st.onGuildUpdate(e) })
+	AddHandler(s, func(s *Session, e *GuildDelete) { st.onGuildDelete(e) })
+	AddHandler(s, func(s *Session, e *ChannelCreate) { st.setChannel(e.Channel) })
+	AddHandler(s, func(s *Session, e *ChannelUpdate) { st.setChannel(e.Channel) })
+	AddHandler(s, func(s *Session, e *ChannelDelete) { st.removeChannel(e.ID) })
+	AddHandler(s, func(s *Session, e *ThreadCreate) { st.setChannel(e.Channel) })
+	AddHandler(s, func(s *Session, e *ThreadUpdate) { st.setChannel(e.Channel) })
+	AddHandler(s, func(s *Session, e *ThreadDelete) { st.removeChannel(e.ID) })
+	AddHandler(s, func(s *Session, e *ThreadListSync) { st.onThreadListSync(e) })
+	AddHandler(s, func(s *Session, e *GuildRoleCreate) { st.setRole(e.GuildID, e.Role) })
+	AddHandler(s, func(s *Session, e *GuildRoleUpdate) { st.setRole(e.GuildID, e.Role) })
+	AddHandler(s, func(s *Session, e *GuildRoleDelete) { st.removeRole(e.GuildID, e.RoleID) })
+	AddHandler(s, func(s *Session, e *GuildEmojisUpdate) { st.onEmojisUpdate(e) })
+	AddHandler(s, func(s *Session, e *GuildMemberAdd) { st.onMemberAdd(e) })
+	AddHandler(s, func(s *Session, e *GuildMemberUpdate) { st.setMembers(e.GuildID, e.Member) })
+	AddHandler(s, func(s *Session, e *GuildMemberRemove) { st.onMemberRemove(e) })
+	AddHandler(s, func(s *Session, e *GuildMembersChunk) { st.setMembers(e.GuildID, e.Members...) })
+	AddHandler(s, func(s *Session, e *VoiceStateUpdate) { st.onVoiceStateUpdate(e) })
+	AddHandler(s, func(s *Session, e *MessageCreate) { st.onMessageCreate(e) })
+	AddHandler(s, func(s *Session, e *MessageUpdate) { st.onMessageUpdate(e) })
+	AddHandler(s, func(s *Session, e *MessageDelete) { st.removeMessages(e.ChannelID, e.ID) })
+	AddHandler(s, func(s *Session, e *MessageDeleteBulk) { st.removeMessages(e.ChannelID, e.IDs...) })
+	return st
+}
+
+func (st *State) onReady(e *ReadyCreate) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.self = e.User
+	st.users[e.User.ID] = e.User
+	for _, g := range e.Guilds {
+		if _, exists := st.guilds[g.ID]; !exists {
+			gs := newGuildState(Guild{ID: g.ID})
+			gs.unavailable = true
+			st.guilds[g.ID] = gs
+		}
+	}
+}
+
+func newGuildState(guild Guild) *guildState {
+	return &guildState{
+		guild:       guild,
+		channelIDs:  make(map[string]struct{}),
+		roles:       make(map[string]Role),
+		emojis:      make(map[string]Emoji),
+		members:     make(map[string]Member),
+		voiceStates: make(map[string]VoiceState),
+	}
+}
+
+// Returns whether members of the guild are cached, the caller must hold the lock
+func (st *State) cachesMembers(gs *guildState) bool {
+	switch st.config.Members {
+	case MemberCacheAll:
+		return true
+	case MemberCacheSmallGuilds:
+		return !gs.large
+	}
+	return false
+}
+
+func (st *State) onGuildCreate(e *GuildCreate) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	if old, exists := st.guilds[e.ID]; exists {
+		st.removeGuild(old)
+	}
+	gs := newGuildState(e.Guild)
+	gs.large = e.Large
+	gs.memberCount = e.MemberCount
+	gs.unavailable = e.Unavailable
+	st.guilds[e.ID] = gs
+	st.setGuildFields(gs, e.Guild)
+
+	for _, c := range e.Channels {
+		c.GuildID = e.ID
+		st.setChannelLocked(c)
+	}
+	for _, c := range e.Threads {
+		c.GuildID = e.ID
+		st.setChannelLocked(c)
+	}
+	for _, m := range e.Members {
+		st.setMemberLocked(gs, m)
+	}
+	if st.config.VoiceStates {
+		for _, vs := range e.VoiceStates {
+			vs.GuildID = e.ID
+			st.setVoiceStateLocked(gs, vs)
+		}
+	}
+}
+
+// Updates the guild and its roles and emojis, the caller must hold the lock
+func (st *State) setGuildFields(gs *guildState, guild Guild) {
+	if st.config.Roles {
+		gs.roles = make(map[string]Role, len(guild.Roles))
+		for _, r := range guild.Roles {
+			gs.roles[r.ID] = r
+		}
+	}
+	if st.config.Emojis {
+		gs.emojis = make(map[string]Emoji, len(guild.Emojis))
+		for _, e := range guild.Emojis {
+			gs.emojis[e.ID] = e
+		}
+	}
+	guild.Roles = nil
+	guild.Emojis = nil
+	gs.guild = guild
+}
+
+func (st *State) onGuildUpdate(e *GuildUpdate) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	gs, exists := st.guilds[e.ID]
+	if !exists {
+		gs = newGuildState(e.Guild)
+		st.guilds[e.ID] = gs
+	}
+	st.setGuildFields(gs, e.Guild)
+}
+
+func (st *State) onGuildDelete(e *GuildDelete) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	gs, exists := st.guilds[e.ID]
+	if !exists {
+		return
+	}
+	if e.Unavailable {
+		// Keep what is cached, the guild is sent again once the outage is over
+		gs.unavailable = true
+		return
+	}
+	st.removeGuild(gs)
+	delete(st.guilds, e.ID)
+}
+
+// Removes the channels and messages of a guild, the caller must hold the lock
+func (st *State) removeGuild(gs *guildState) {
+	for id := range gs.channelIDs {
+		delete(st.channels, id)
+		delete(st.messages, id)
+	}
+}
+
+func (st *State) setChannel(c Channel) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.setChannelLocked(c)
+}
+
+func (st *State) setChannelLocked(c Channel) {
+	if c.IsThread() && !st.config.Threads || !c.IsThread() && !st.config.Channels {
+		return
+	}
+	st.channels[c.ID] = c
+	if gs, exists := st.guilds[c.GuildID]; exists {
+		gs.channelIDs[c.ID] = struct{}{}
+	}
+}
+
+func (st *State) removeChannel(id string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.removeChannelLocked(id)
+}
+
+func (st *State) removeChannelLocked(id string) {
+	if c, exists := st.channels[id]; exists {
+		if gs, exists := st.guilds[c.GuildID]; exists {
+			delete(gs.channelIDs, id)
+		}
+	}
+	delete(st.channels, id)
+	delete(st.messages, id)
+}
+
+func (st *State) onThreadListSync(e *ThreadListSync) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	// The threads replace the cached threads of the channels, or the whole guild if none are given
+	for id, c := range st.channels {
+		if c.GuildID != e.GuildID || !c.IsThread() {
+			continue
+		}
+		if len(e.ChannelIDs) == 0 || slices.Contains(e.ChannelIDs, c.ParentID) {
+			st.removeChannelLocked(id)
+		}
+	}
+	for _, c := range e.Threads {
+		c.GuildID = e.GuildID
+		st.setChannelLocked(c)
+	}
+}
+
+func (st *State) setRole(guildID string, role Role) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	if gs, exists := st.guilds[guildID]; exists && st.config.Roles {
+		gs.roles[role.ID] = role
+	}
+}
+
+func (st *State) removeRole(guildID string, roleID string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		delete(gs.roles, roleID)
+	}
+}
+
+func (st *State) onEmojisUpdate(e *GuildEmojisUpdate) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	gs, exists := st.guilds[e.GuildID]
+	if !exists || !st.config.Emojis {
+		return
+	}
+	gs.emojis = make(map[string]Emoji, len(e.Emojis))
+	for _, emoji := range e.Emojis {
+		gs.emojis[emoji.ID] = emoji
+	}
+}
+
+// Stores a member and its user if the member policy allows it, the caller must hold the lock
+func (st *State) setMemberLocked(gs *guildState, m Member) {
+	// The bot's own member is always kept, ChannelPermissions needs it
+	if m.User == nil || !st.cachesMembers(gs) && m.User.ID != st.self.ID {
+		return
+	}
+	// The user is kept once for all guilds, which also drops the pointer into the event
+	st.users[m.User.ID] = *m.User
+	userID := m.User.ID
+	m.User = nil
+	m.GuildID = gs.guild.ID
+	gs.members[userID] = m
+}
+
+// Returns a copy of a cached member with its own copy of the user, the caller must hold the lock
+func (st *State) memberLocked(gs *guildState, userID string) (Member, bool) {
+	m, exists := gs.members[userID]
+	if !exists {
+		return Member{}, false
+	}
+	user := st.users[userID]
+	m.User = &user
+	return m, true
+}
+
+func (st *State) setMembers(guildID string, members ...Member) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	gs, exists := st.guilds[guildID]
+	if !exists {
+		return
+	}
+	for _, m := range members {
+		st.setMemberLocked(gs, m)
+	}
+}
+
+func (st *State) onMemberAdd(e *GuildMemberAdd) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	gs, exists := st.guilds[e.GuildID]
+	if !exists {
+		return
+	}
+	gs.memberCount++
+	st.setMemberLocked(gs, e.Member)
+}
+
+func (st *State) onMemberRemove(e *GuildMemberRemove) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	gs, exists := st.guilds[e.GuildID]
+	if !exists {
+		return
+	}
+	gs.memberCount--
+	delete(gs.members, e.User.ID)
+	delete(gs.voiceStates, e.User.ID)
+}
+
+func (st *State) onVoiceStateUpdate(e *VoiceStateUpdate) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	gs, exists := st.guilds[e.GuildId]
+	if !exists {
+		return
+	}
+	if e.Member != nil {
+		st.setMemberLocked(gs, *e.Member)
+	}
+	if st.config.VoiceStates {
+		st.setVoiceStateLocked(gs, e.VoiceState())
+	}
+}
+
+// Stores a voice state without its member, or removes it if the user left, the caller must hold the lock
+func (st *State) setVoiceStateLocked(gs *guildState, vs VoiceState) {
+	if vs.ChannelID == "" {
+		delete(gs.voiceStates, vs.UserID)
+		return
+	}
+	vs.Member = nil
+	gs.voiceStates[vs.UserID] = vs
+}
+
+func (st *State) onMessageCreate(e *MessageCreate) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	message := e.Message()
+	if e.Member != nil {
+		if gs, exists := st.guilds[e.GuildID]; exists {
+			// Copied so the member doesn't keep the whole message alive
+			author := message.Author
+			member := *e.Member
+			member.User = &author
+			st.setMemberLocked(gs, member)
+		}
+	}
+
+	if st.config.MaxMessages <= 0 {
+		return
+	}
+	messages := append(st.messages[e.ChannelID], message)
+	if len(messages) > st.config.MaxMessages {
+		// Copy so the dropped messages can be garbage collected
+		messages = slices.Clone(messages[len(messages)-st.config.MaxMessages:])
+	}
+	st.messages[e.ChannelID] = messages
+}
+
+func (st *State) onMessageUpdate(e *MessageUpdate) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	messages := st.messages[e.ChannelID]
+	for i := range messages {
+		if messages[i].ID == e.ID {
+			messages[i] = e.Message
+			return
+		}
+	}
+}
+
+func (st *State) removeMessages(channelID string, ids ...string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	messages, exists := st.messages[channelID]
+	if !exists {
+		return
+	}
+	st.messages[channelID] = slices.DeleteFunc(messages, func(m Message) bool {
+		return slices.Contains(ids, m.ID)
+	})
+}
+
+// Returns the bot user
+func (st *State) Self() User {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	return st.self
+}
+
+// Returns a guild with its roles and emojis
+func (st *State) Guild(guildID string) (Guild, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	gs, exists := st.guilds[guildID]
+	if !exists {
+		return Guild{}, false
+	}
+	guild := gs.guild
+	guild.Roles = sortedValues(gs.roles, func(a, b Role) int { return a.Position - b.Position })
+	guild.Emojis = sortedValues(gs.emojis, nil)
+	return guild, true
+}
+
+// Returns the IDs of all guilds, including unavailable ones
+func (st *State) GuildIDs() []string {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	ids := make([]string, 0, len(st.guilds))
+	for id := range st.guilds {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Returns whether a guild is unavailable due to an outage, or hasn't been received yet after READY
+func (st *State) GuildUnavailable(guildID string) bool {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	gs, exists := st.guilds[guildID]
+	return exists && gs.unavailable
+}
+
+// Returns the number of members of a guild, including those that aren't cached
+func (st *State) MemberCount(guildID string) int {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		return gs.memberCount
+	}
+	return 0
+}
+
+// Returns whether a guild is large, see MemberCacheSmallGuilds
+func (st *State) GuildLarge(guildID string) bool {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	gs, exists := st.guilds[guildID]
+	return exists && gs.large
+}
+
+// Returns a channel or thread
+func (st *State) Channel(channelID string) (Channel, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	c, exists := st.channels[channelID]
+	return c, exists
+}
+
+// Returns the channels and threads of a guild, sorted by position
+func (st *State) GuildChannels(guildID string) []Channel {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	gs, exists := st.guilds[guildID]
+	if !exists {
+		return nil
+	}
+	channels := make([]Channel, 0, len(gs.channelIDs))
+	for id := range gs.channelIDs {
+		channels = append(channels, st.channels[id])
+	}
+	slices.SortFunc(channels, func(a, b Channel) int {
+		if a.Position != b.Position {
+			return a.Position - b.Position
+		}
+		return compareSnowflakes(a.ID, b.ID)
+	})
+	return channels
+}
+
+func (st *State) Role(guildID string, roleID string) (Role, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		role, exists := gs.roles[roleID]
+		return role, exists
+	}
+	return Role{}, false
+}
+
+// Returns the roles of a guild, sorted by position
+func (st *State) Roles(guildID string) []Role {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		return sortedValues(gs.roles, func(a, b Role) int { return a.Position - b.Position })
+	}
+	return nil
+}
+
+func (st *State) Emoji(guildID string, emojiID string) (Emoji, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		emoji, exists := gs.emojis[emojiID]
+		return emoji, exists
+	}
+	return Emoji{}, false
+}
+
+func (st *State) Emojis(guildID string) []Emoji {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		return sortedValues(gs.emojis, nil)
+	}
+	return nil
+}
+
+// Returns a member with its User set
+func (st *State) Member(guildID string, userID string) (Member, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		return st.memberLocked(gs, userID)
+	}
+	return Member{}, false
+}
+
+// Returns the cached members of a guild, which may be all or some of them depending on the member policy
+func (st *State) Members(guildID string) []Member {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	gs, exists := st.guilds[guildID]
+	if !exists {
+		return nil
+	}
+	ids := make([]string, 0, len(gs.members))
+	for id := range gs.members {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, compareSnowflakes)
+	members := make([]Member, 0, len(ids))
+	for _, id := range ids {
+		m, _ := st.memberLocked(gs, id)
+		members = append(members, m)
+	}
+	return members
+}
+
+// Returns a user seen as a member or message author
+func (st *State) User(userID string) (User, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	u, exists := st.users[userID]
+	return u, exists
+}
+
+func (st *State) Message(channelID string, messageID string) (Message, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	for _, m := range st.messages[channelID] {
+		if m.ID == messageID {
+			return m, true
+		}
+	}
+	return Message{}, false
+}
+
+// Returns the voice state of a user in a voice channel of the guild
+func (st *State) VoiceState(guildID string, userID string) (VoiceState, bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		vs, exists := gs.voiceStates[userID]
+		return vs, exists
+	}
+	return VoiceState{}, false
+}
+
+// Returns the voice states of all users in voice channels of the guild
+func (st *State) VoiceStates(guildID string) []VoiceState {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if gs, exists := st.guilds[guildID]; exists {
+		return sortedValues(gs.voiceStates, nil)
+	}
+	return nil
+}
+
+// Returns the voice channel of a user, known is false if voice states of the guild aren't cached
+func (st *State) voiceChannel(guildID string, userID string) (channelID string, known bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	gs, exists := st.guilds[guildID]
+	if !st.config.VoiceStates || !exists || gs.unavailable {
+		return "", false
+	}
+	return gs.voiceStates[userID].ChannelID, true
+}
+
+// Computes the permissions of a member in a channel from the cache,
+// cached is false if anything needed for it isn't cached
+func (st *State) channelPermissions(channelID string, userID string) (perms Permissions, cached bool) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	if !st.config.Roles {
+		return 0, false
+	}
+	channel, exists := st.channels[channelID]
+	if !exists {
+		return 0, false
+	}
+	gs, exists := st.guilds[channel.GuildID]
+	if !exists || gs.unavailable {
+		return 0, false
+	}
+	member, exists := gs.members[userID]
+	if !exists {
+		return 0, false
+	}
+	overwrites := channel.PermissionOverwrites
+	if channel.IsThread() {
+		parent, exists := st.channels[channel.ParentID]
+		if !exists {
+			return 0, false
+		}
+		overwrites = parent.PermissionOverwrites
+	}
+
+	roles := make([]Role, 0, len(gs.roles))
+	for _, role := range gs.roles {
+		roles = append(roles, role)
+	}
+	perms = ComputePermissions(gs.guild.ID, gs.guild.OwnerID, userID, member.Roles, roles, overwrites)
+	if member.TimedOut() {
+		perms = ApplyTimeout(perms)
+	}
+	return perms, true
+}
+
+// Returns the cached messages of a channel, oldest first
+func (st *State) Messages(channelID string) []Message {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	return slices.Clone(st.messages[channelID])
+}
+
+// Returns the values of a map sorted by compare, or by key if compare is nil
+func sortedValues[V any](m map[string]V, compare func(a, b V) int) []V {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, compareSnowflakes)
+	values := make([]V, 0, len(m))
+	for _, k := range keys {
+		values = append(values, m[k])
+	}
+	if compare != nil {
+		slices.SortStableFunc(values, compare)
+	}
+	return values
+}
+
+// Orders snowflakes by creation time
+func compareSnowflakes(a string, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package discordgowrap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStateVoiceStates(t *testing.T) {
+	st := &State{
+		config: StateConfig{VoiceStates: true},
+		guilds: map[string]*guildState{"1": newGuildState(Guild{ID: "1"})},
+		users:  make(map[string]User),
+	}
+
+	var e VoiceStateUpdate
+	data := `{"guild_id":"1","channel_id":"2","user_id":"3","session_id":"s","self_mute":true,"suppress":true}`
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		t.Fatal(err)
+	}
+	st.onVoiceStateUpdate(&e)
+	vs, exists := st.VoiceState("1", "3")
+	if !exists {
+		t.Fatal("voice state was not cached")
+	}
+	want := VoiceState{GuildID: "1", ChannelID: "2", UserID: "3", SessionID: "s", SelfMute: true, Suppress: true}
+	if vs != want {
+		t.Errorf("got %+v, want %+v", vs, want)
+	}
+
+	st.onMemberRemove(&GuildMemberRemove{GuildID: "1", User: User{ID: "3"}})
+	if _, exists := st.VoiceState("1", "3"); exists {
+		t.Error("voice state was kept after the member left the guild")
+	}
+}
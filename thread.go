@@ -0,0 +1,320 @@
+// Handles threads and forum posts
+package discordgowrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"time"
+)
+
+// https://discord.com/developers/docs/resources/channel#thread-metadata-object
+type ThreadMetadata struct {
+	Archived            bool       `json:"archived"`
+	AutoArchiveDuration int        `json:"auto_archive_duration"` // minutes
+	ArchiveTimestamp    time.Time  `json:"archive_timestamp"`
+	Locked              bool       `json:"locked"`
+	Invitable           *bool      `json:"invitable,omitempty"` // only set for private threads
+	CreateTimestamp     *time.Time `json:"create_timestamp,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#thread-member-object
+type ThreadMember struct {
+	ID            string    `json:"id,omitempty"` // thread ID
+	UserID        string    `json:"user_id,omitempty"`
+	JoinTimestamp time.Time `json:"join_timestamp"`
+	Flags         int       `json:"flags"`
+	// Only set by ThreadMembers
+	Member *Member `json:"member,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#forum-tag-object
+type ForumTag struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Moderated bool   `json:"moderated"`
+	EmojiID   string `json:"emoji_id,omitempty"`
+	EmojiName string `json:"emoji_name,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#start-thread-without-message-json-params
+type ThreadStart struct {
+	Name                string `json:"name"`
+	AutoArchiveDuration int    `json:"auto_archive_duration,omitempty"` // 60, 1440, 4320 or 10080 minutes
+	RateLimitPerUser    int    `json:"rate_limit_per_user,omitempty"`
+	// Only used without a message, defaults to ChannelTypePrivateThread
+	Type int `json:"type,omitempty"`
+	// Whether non-moderators can add other non-moderators, only used by private threads
+	Invitable *bool `json:"invitable,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#start-thread-in-forum-or-media-channel
+type ForumThreadStart struct {
+	Name                string      `json:"name"`
+	AutoArchiveDuration int         `json:"auto_archive_duration,omitempty"`
+	RateLimitPerUser    int         `json:"rate_limit_per_user,omitempty"`
+	Message             MessageSend `json:"message"`
+	AppliedTags         []string    `json:"applied_tags,omitempty"`
+}
+
+// Threads returned when listing active or archived threads.
+// Members only contains the thread members of the current user.
+type ThreadsList struct {
+	Threads []Channel      `json:"threads"`
+	Members []ThreadMember `json:"members"`
+	HasMore bool           `json:"has_more,omitempty"`
+}
+
+// https://discord.com/developers/docs/events/gateway-events#thread-create
+type ThreadCreate struct {
+	Channel
+	NewlyCreated bool `json:"newly_created,omitempty"`
+}
+
+func (*ThreadCreate) EventType() string { return TypeThreadCreate }
+
+type ThreadUpdate struct {
+	Channel
+}
+
+func (*ThreadUpdate) EventType() string { return TypeThreadUpdate }
+
+// https://discord.com/developers/docs/events/gateway-events#thread-delete
+type ThreadDelete struct {
+	ID       string `json:"id"`
+	GuildID  string `json:"guild_id"`
+	ParentID string `json:"parent_id"`
+	Type     int    `json:"type"`
+}
+
+func (*ThreadDelete) EventType() string { return TypeThreadDelete }
+
+// https://discord.com/developers/docs/events/gateway-events#thread-list-sync
+type ThreadListSync struct {
+	GuildID    string         `json:"guild_id"`
+	ChannelIDs []string       `json:"channel_ids,omitempty"`
+	Threads    []Channel      `json:"threads"`
+	Members    []ThreadMember `json:"members"`
+}
+
+func (*ThreadListSync) EventType() string { return TypeThreadListSync }
+
+// https://discord.com/developers/docs/events/gateway-events#thread-member-update
+type ThreadMemberUpdate struct {
+	ThreadMember
+	GuildID string `json:"guild_id"`
+}
+
+func (*ThreadMemberUpdate) EventType() string { return TypeThreadMemberUpdate }
+
+// https://discord.com/developers/docs/events/gateway-events#thread-members-update
+type ThreadMembersUpdate struct {
+	ID               string         `json:"id"`
+	GuildID          string         `json:"guild_id"`
+	MemberCount      int            `json:"member_count"`
+	AddedMembers     []ThreadMember `json:"added_members,omitempty"`
+	RemovedMemberIDs []string       `json:"removed_member_ids,omitempty"`
+}
+
+func (*ThreadMembersUpdate) EventType() string { return TypeThreadMembersUpdate }
+
+// Starts a thread on an existing message
+func (s *Session) StartThreadFromMessage(ctx context.Context, channelID string, messageID string, data ThreadStart, opts ...RequestOption) (*Channel, error) {
+	url := fmt.Sprintf("%s/channels/%s/messages/%s/threads", apiBase, channelID, messageID)
+	data.Type = 0
+	var thread Channel
+	if err := s.requestJSON(ctx, "POST", url, data, &thread, opts...); err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+// Starts a thread that isn't attached to a message
+func (s *Session) StartThread(ctx context.Context, channelID string, data ThreadStart, opts ...RequestOption) (*Channel, error) {
+	url := fmt.Sprintf("%s/channels/%s/threads", apiBase, channelID)
+	if data.Type == 0 {
+		data.Type = ChannelTypePrivateThread
+	}
+	var thread Channel
+	if err := s.requestJSON(ctx, "POST", url, data, &thread, opts...); err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+// Creates a post in a forum or media channel, data.Message.Files are uploaded with it
+func (s *Session) StartForumThread(ctx context.Context, channelID string, data ForumThreadStart, opts ...RequestOption) (*Channel, error) {
+	url := fmt.Sprintf("%s/channels/%s/threads", apiBase, channelID)
+	var thread Channel
+	if len(data.Message.Files) == 0 {
+		if err := s.requestJSON(ctx, "POST", url, data, &thread, opts...); err != nil {
+			return nil, err
+		}
+		return &thread, nil
+	}
+
+	// The attachments belong in message, so only message goes through attachFiles
+	message, err := attachFiles(data.Message, data.Message.Files)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(struct {
+		ForumThreadStart
+		Message json.RawMessage `json:"message"`
+	}{data, message})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %v", err)
+	}
+	recvBody, err := s.request(ctx, "POST", url, multipartBody(payload, data.Message.Files), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshalResponse(recvBody, &thread); err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+func (s *Session) JoinThread(ctx context.Context, threadID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/thread-members/@me", apiBase, threadID)
+	return s.requestJSON(ctx, "PUT", url, nil, nil, opts...)
+}
+
+func (s *Session) LeaveThread(ctx context.Context, threadID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/thread-members/@me", apiBase, threadID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+func (s *Session) AddThreadMember(ctx context.Context, threadID string, userID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/thread-members/%s", apiBase, threadID, userID)
+	return s.requestJSON(ctx, "PUT", url, nil, nil, opts...)
+}
+
+func (s *Session) RemoveThreadMember(ctx context.Context, threadID string, userID string, opts ...RequestOption) error {
+	url := fmt.Sprintf("%s/channels/%s/thread-members/%s", apiBase, threadID, userID)
+	return s.requestJSON(ctx, "DELETE", url, nil, nil, opts...)
+}
+
+func (s *Session) GetThreadMember(ctx context.Context, threadID string, userID string, opts ...RequestOption) (*ThreadMember, error) {
+	url := fmt.Sprintf("%s/channels/%s/thread-members/%s", apiBase, threadID, userID)
+	var member ThreadMember
+	if err := s.requestJSON(ctx, "GET", url, nil, &member, opts...); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Returns an iterator over the members of a thread with their guild members,
+// fetched 100 at a time. Requires the GUILD_MEMBERS privileged intent.
+func (s *Session) ThreadMembers(ctx context.Context, threadID string, opts ...RequestOption) iter.Seq2[ThreadMember, error] {
+	return func(yield func(ThreadMember, error) bool) {
+		after := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(ThreadMember{}, err)
+				return
+			}
+
+			params := url.Values{}
+			// limit and after are ignored without with_member
+			params.Set("with_member", "true")
+			params.Set("limit", "100")
+			if after != "" {
+				params.Set("after", after)
+			}
+			endpoint := fmt.Sprintf("%s/channels/%s/thread-members?%s", apiBase, threadID, params.Encode())
+			var page []ThreadMember
+			if err := s.requestJSON(ctx, "GET", endpoint, nil, &page, opts...); err != nil {
+				yield(ThreadMember{}, err)
+				return
+			}
+			for _, member := range page {
+				if !yield(member, nil) {
+					return
+				}
+			}
+			if len(page) < 100 {
+				return
+			}
+			last := page[len(page)-1].UserID
+			if last == after {
+				// The page didn't advance, stop instead of yielding it again
+				return
+			}
+			after = last
+		}
+	}
+}
+
+// Returns all active threads in a guild that the bot can see
+func (s *Session) ActiveThreads(ctx context.Context, guildID string, opts ...RequestOption) (*ThreadsList, error) {
+	url := fmt.Sprintf("%s/guilds/%s/threads/active", apiBase, guildID)
+	var list ThreadsList
+	if err := s.requestJSON(ctx, "GET", url, nil, &list, opts...); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Which archived threads to list
+const (
+	ArchivedThreadsPublic        = "public"
+	ArchivedThreadsPrivate       = "private"
+	ArchivedThreadsJoinedPrivate = "joined-private"
+)
+
+// Returns an iterator over the archived threads in a channel, newest first.
+// kind is one of the ArchivedThreads* constants.
+func (s *Session) ArchivedThreads(ctx context.Context, channelID string, kind string, opts ...RequestOption) iter.Seq2[Channel, error] {
+	return func(yield func(Channel, error) bool) {
+		var endpoint string
+		switch kind {
+		case ArchivedThreadsPublic, ArchivedThreadsPrivate:
+			endpoint = fmt.Sprintf("%s/channels/%s/threads/archived/%s", apiBase, channelID, kind)
+		case ArchivedThreadsJoinedPrivate:
+			endpoint = fmt.Sprintf("%s/channels/%s/users/@me/threads/archived/private", apiBase, channelID)
+		default:
+			yield(Channel{}, fmt.Errorf("unknown archived thread kind %q", kind))
+			return
+		}
+
+		before := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(Channel{}, err)
+				return
+			}
+
+			params := url.Values{}
+			params.Set("limit", "100")
+			if before != "" {
+				params.Set("before", before)
+			}
+			var list ThreadsList
+			if err := s.requestJSON(ctx, "GET", endpoint+"?"+params.Encode(), nil, &list, opts...); err != nil {
+				yield(Channel{}, err)
+				return
+			}
+			for _, thread := range list.Threads {
+				if !yield(thread, nil) {
+					return
+				}
+			}
+			if !list.HasMore || len(list.Threads) == 0 {
+				return
+			}
+
+			// Joined private threads page by thread ID, the others by archive timestamp
+			last := list.Threads[len(list.Threads)-1]
+			if kind == ArchivedThreadsJoinedPrivate {
+				before = last.ID
+			} else if last.ThreadMetadata != nil {
+				before = last.ThreadMetadata.ArchiveTimestamp.Format(time.RFC3339Nano)
+			} else {
+				return
+			}
+		}
+	}
+}
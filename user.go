@@ -0,0 +1,32 @@
+// Handles users
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+)
+
+// https://discord.com/developers/docs/resources/user#user-object
+type User struct {
+	ID            string `json:"id"`
+	Name          string `json:"username"`
+	GlobalName    string `json:"global_name,omitempty"`
+	Discriminator string `json:"discriminator,omitempty"`
+	Avatar        string `json:"avatar,omitempty"`
+	Bot           bool   `json:"bot,omitempty"`
+	System        bool   `json:"system,omitempty"`
+}
+
+// Returns the string that mentions the user in a message
+func (u *User) Mention() string {
+	return "<@" + u.ID + ">"
+}
+
+func (s *Session) GetUser(ctx context.Context, userID string, opts ...RequestOption) (*User, error) {
+	url := fmt.Sprintf("%s/users/%s", apiBase, userID)
+	var user User
+	if err := s.requestJSON(ctx, "GET", url, nil, &user, opts...); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
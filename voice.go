@@ -100,7 +100,7 @@ func (v *voiceConnection) SetSpeaking(speaking bool) bool {
 		log.Printf("[VC] Error sending SPEAKING for voice channel: %v\n", err)
 		return false
 	}
-	fmt.Printf("[VC] Sent SPEAKING %d for guild %s\n", speaking, v.guildId)
+	fmt.Printf("[VC] Sent SPEAKING %t for guild %s\n", speaking, v.guildId)
 	return true
 }
 
@@ -0,0 +1,35 @@
+// Handles webhooks
+package discordgowrap
+
+import (
+	"context"
+	"fmt"
+)
+
+// https://discord.com/developers/docs/resources/webhook#execute-webhook-jsonform-params
+type WebhookParams struct {
+	Content         string           `json:"content,omitempty"`
+	Username        string           `json:"username,omitempty"`
+	AvatarURL       string           `json:"avatar_url,omitempty"`
+	TTS             bool             `json:"tts,omitempty"`
+	Embeds          []Embed          `json:"embeds,omitempty"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Components      []Component      `json:"components,omitempty"`
+	Flags           int              `json:"flags,omitempty"`
+	ThreadName      string           `json:"thread_name,omitempty"`
+	Poll            *PollCreate      `json:"poll,omitempty"`
+	Files           []File           `json:"-"`
+}
+
+// Executes a webhook. If wait is true the created message is returned, otherwise nil.
+func (s *Session) ExecuteWebhook(ctx context.Context, webhookID string, token string, data WebhookParams, wait bool, opts ...RequestOption) (*Message, error) {
+	url := fmt.Sprintf("%s/webhooks/%s/%s?wait=%t", apiBase, webhookID, token, wait)
+	if !wait {
+		return nil, s.requestWithFiles(ctx, "POST", url, data, data.Files, nil, opts...)
+	}
+	var msg Message
+	if err := s.requestWithFiles(ctx, "POST", url, data, data.Files, &msg, opts...); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}